@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -22,10 +23,6 @@ import (
 	"github.com/threefoldtech/zos/pkg/version"
 )
 
-const (
-	redisSocket = "unix:///var/run/redis.sock"
-)
-
 const (
 	module = "identityd"
 )
@@ -41,11 +38,18 @@ func main() {
 	app.Initialize()
 
 	var (
-		broker   string
-		root     string
-		interval int
-		ver      bool
-		debug    bool
+		broker    string
+		root      string
+		interval  int
+		ver       bool
+		debug     bool
+		window    string
+		dryRun    bool
+		logFormat string
+		logLevel  string
+		selftest  bool
+		pin       string
+		downgrade bool
 
 		id      bool
 		net     bool
@@ -54,16 +58,34 @@ func main() {
 	)
 
 	flag.StringVar(&root, "root", "/var/cache/modules/identityd", "root working directory of the module")
-	flag.StringVar(&broker, "broker", redisSocket, "connection string to broker")
+	flag.StringVar(&broker, "broker", environment.Broker(), "connection string to broker")
 	flag.IntVar(&interval, "interval", 600, "interval in seconds between update checks, default to 600")
 	flag.BoolVar(&ver, "v", false, "show version and exit")
+	flag.StringVar(&logFormat, "log-format", app.LogFormatConsole, "log output format (console or json)")
+	flag.StringVar(&logLevel, "log-level", "", "log level (debug, info, warn or error), defaults to info or debug if the node booted in debug mode")
 	flag.BoolVar(&debug, "d", false, "when set, no self update is done before upgrading")
+	flag.BoolVar(&selftest, "selftest", false, "runs checks to diagnose the node, prints the results and exits")
+	flag.BoolVar(&dryRun, "dry-run", false, "prints what the next update would do and exits")
+	flag.StringVar(&pin, "pin", "", "pin the node to this version (e.g. v3.10.2) instead of following the latest release")
+	flag.BoolVar(&downgrade, "allow-downgrade", false, "allow moving to a pinned version older than the current one")
+	flag.StringVar(&window, "window", "", "only apply updates inside this daily UTC window (e.g. 02:00-04:00)")
 	flag.BoolVar(&id, "id", false, "[deprecated] prints the node ID and exits")
 	flag.BoolVar(&net, "net", false, "prints the node network and exits")
 	flag.BoolVar(&farm, "farm", false, "prints the node farm id and exits")
 	flag.BoolVar(&address, "address", false, "prints the node ss58 address and exits")
 
 	flag.Parse()
+
+	if err := app.SetLogFormat(logFormat); err != nil {
+		log.Fatal().Err(err).Msg("invalid log format")
+	}
+
+	if len(logLevel) != 0 {
+		if err := app.SetLogLevel(logLevel); err != nil {
+			log.Fatal().Err(err).Msg("invalid log level")
+		}
+	}
+
 	if ver {
 		version.ShowAndExit(false)
 	}
@@ -97,10 +119,48 @@ func main() {
 		os.Exit(0)
 	}
 
+	if selftest {
+		if !selfTest(root, broker) {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	if err := os.MkdirAll(root, 0750); err != nil {
 		log.Fatal().Err(err).Str("root", root).Msg("failed to create root directory")
 	}
 
+	opts := []upgrade.UpgraderOption{
+		upgrade.NoZosUpgrade(debug),
+		upgrade.ZbusClient(client),
+	}
+
+	if len(window) != 0 {
+		w, err := upgrade.ParseWindow(window)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid upgrade window")
+		}
+		log.Info().Stringer("window", w).Msg("updates are only applied inside the upgrade window")
+		opts = append(opts, upgrade.UpgradeWindow(w))
+	}
+
+	if len(pin) != 0 {
+		log.Info().Str("version", pin).Bool("downgrade", downgrade).Msg("node is pinned to version")
+		opts = append(opts, upgrade.PinVersion(pin, downgrade))
+	}
+
+	upgrader, err := upgrade.NewUpgrader(root, opts...)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize upgrader")
+	}
+
+	if dryRun {
+		if err := printPlan(upgrader); err != nil {
+			log.Fatal().Err(err).Msg("failed to plan update")
+		}
+		os.Exit(0)
+	}
+
 	// 2. Register the node to BCDB
 	// at this point we are running latest version
 	idMgr, err := getIdentityMgr(root, debug)
@@ -108,11 +168,6 @@ func main() {
 		log.Fatal().Err(err).Msg("failed to create identity manager")
 	}
 
-	upgrader, err := upgrade.NewUpgrader(root, upgrade.NoZosUpgrade(debug), upgrade.ZbusClient(client))
-	if err != nil {
-		log.Fatal().Err(err).Msg("failed to initialize upgrader")
-	}
-
 	monitor := newVersionMonitor(10*time.Second, upgrader.Version())
 	// 3. start zbus server to serve identity interface
 	log.Info().Stringer("version", monitor.GetVersion()).Msg("current")
@@ -176,3 +231,14 @@ func getIdentityMgr(root string, debug bool) (pkg.IdentityManager, error) {
 
 	return manager, nil
 }
+
+func printPlan(upgrader *upgrade.Upgrader) error {
+	plan, err := upgrader.Plan(context.Background())
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(plan)
+}
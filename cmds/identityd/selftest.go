@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/threefoldtech/zos/pkg/environment"
+	"github.com/threefoldtech/zos/pkg/identity"
+	"github.com/threefoldtech/zos/pkg/upgrade"
+	"github.com/threefoldtech/zos/pkg/utils"
+	"github.com/threefoldtech/zos/pkg/zinit"
+)
+
+// selfCheck is a single check run by the self test. A check
+// returns a short detail on success
+type selfCheck struct {
+	name string
+	run  func() (string, error)
+}
+
+// selfTest runs a series of checks to diagnose the node and prints
+// pass/fail for each of them. It returns false if any check failed
+func selfTest(root, broker string) bool {
+	checks := []selfCheck{
+		{name: "broker reachable", run: func() (string, error) {
+			return "", checkBroker(broker)
+		}},
+		{name: "zinit reachable", run: func() (string, error) {
+			_, err := zinit.Default().List()
+			return "", err
+		}},
+		{name: "services healthy", run: checkServices},
+		{name: "identity seed loadable", run: func() (string, error) {
+			return checkSeed(root)
+		}},
+		{name: "chain reachable", run: checkChain},
+		{name: "boot method detected", run: func() (string, error) {
+			return string(upgrade.Boot{}.DetectBootMethod()), nil
+		}},
+		{name: "current version readable", run: func() (string, error) {
+			var boot upgrade.Boot
+			if _, err := boot.Current(); err != nil {
+				return "", err
+			}
+			return boot.Version().String(), nil
+		}},
+	}
+
+	ok := true
+	for _, check := range checks {
+		detail, err := check.run()
+		if err != nil {
+			ok = false
+			fmt.Printf("[FAIL] %s: %s\n", check.name, err)
+			continue
+		}
+
+		if len(detail) != 0 {
+			fmt.Printf("[PASS] %s (%s)\n", check.name, detail)
+		} else {
+			fmt.Printf("[PASS] %s\n", check.name)
+		}
+	}
+
+	return ok
+}
+
+func checkBroker(broker string) error {
+	con, err := utils.NewRedisConn(broker)
+	if err != nil {
+		return err
+	}
+	defer con.Close()
+
+	_, err = con.Do("PING")
+	return err
+}
+
+// checkServices fails if any zinit service failed to run
+func checkServices() (string, error) {
+	services, err := zinit.Default().List()
+	if err != nil {
+		return "", err
+	}
+
+	var failed []string
+	for name, state := range services {
+		if state.Any(zinit.ServiceStateError, zinit.ServiceStateFailure) {
+			failed = append(failed, name)
+		}
+	}
+
+	if len(failed) != 0 {
+		sort.Strings(failed)
+		return "", fmt.Errorf("services in error state: %s", strings.Join(failed, ", "))
+	}
+
+	return fmt.Sprintf("%d services", len(services)), nil
+}
+
+// checkSeed loads the node key without generating a new one if it
+// does not exist
+func checkSeed(root string) (string, error) {
+	key, err := identity.LoadKey(root)
+	if err != nil {
+		return "", err
+	}
+
+	return identity.KeyPairFromKey(key).Identity(), nil
+}
+
+func checkChain() (string, error) {
+	mgr, err := environment.GetSubstrate()
+	if err != nil {
+		return "", err
+	}
+
+	cl, err := mgr.Substrate()
+	if err != nil {
+		return "", err
+	}
+	defer cl.Close()
+
+	return "", nil
+}
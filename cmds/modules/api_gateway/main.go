@@ -29,7 +29,7 @@ var Module cli.Command = cli.Command{
 		&cli.StringFlag{
 			Name:  "broker",
 			Usage: "connection string to the message `BROKER`",
-			Value: "unix:///var/run/redis.sock",
+			Value: environment.Broker(),
 		},
 		&cli.UintFlag{
 			Name:  "workers",
@@ -13,6 +13,7 @@ import (
 
 	"github.com/threefoldtech/zbus"
 	"github.com/threefoldtech/zos/pkg/container"
+	"github.com/threefoldtech/zos/pkg/environment"
 	"github.com/threefoldtech/zos/pkg/utils"
 )
 
@@ -31,7 +32,7 @@ var Module cli.Command = cli.Command{
 		&cli.StringFlag{
 			Name:  "broker",
 			Usage: "connection string to the message `BROKER`",
-			Value: "unix:///var/run/redis.sock",
+			Value: environment.Broker(),
 		},
 		&cli.StringFlag{
 			Name:  "congainerd",
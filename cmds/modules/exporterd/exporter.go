@@ -0,0 +1,242 @@
+package exporterd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/threefoldtech/zbus"
+	"github.com/threefoldtech/zos/pkg/stubs"
+)
+
+const (
+	// collectTimeout is how long a single module is given to answer
+	collectTimeout = 10 * time.Second
+)
+
+// desc describes a metric family
+type desc struct {
+	name string
+	kind string
+	help string
+}
+
+var (
+	moduleUp = desc{"zos_module_up", "gauge", "Whether the module answered during the last collection."}
+
+	networkRx     = desc{"zos_network_receive_bytes_total", "counter", "Bytes received on the wireguard interface of the network."}
+	networkTx     = desc{"zos_network_transmit_bytes_total", "counter", "Bytes sent on the wireguard interface of the network."}
+	networkPeers  = desc{"zos_network_peers", "gauge", "Number of wireguard peers of the network."}
+	peerRx        = desc{"zos_network_peer_receive_bytes_total", "counter", "Bytes received from the wireguard peer."}
+	peerTx        = desc{"zos_network_peer_transmit_bytes_total", "counter", "Bytes sent to the wireguard peer."}
+	peerHandshake = desc{"zos_network_peer_last_handshake_seconds", "gauge", "Unix time of the last handshake with the wireguard peer."}
+
+	vdiskSize = desc{"zos_vdisk_size_bytes", "gauge", "Size of the virtual disk."}
+	vdiskUsed = desc{"zos_vdisk_used_bytes", "gauge", "Bytes allocated by the virtual disk on the storage pool."}
+)
+
+type family struct {
+	desc
+	samples []string
+}
+
+// collection groups samples by family so they can be written in
+// the prometheus text format
+type collection struct {
+	order    []string
+	families map[string]*family
+}
+
+func newCollection() *collection {
+	return &collection{families: make(map[string]*family)}
+}
+
+// add a sample to the collection, labels are given as name, value pairs
+func (c *collection) add(d desc, value float64, labels ...string) {
+	f, ok := c.families[d.name]
+	if !ok {
+		f = &family{desc: d}
+		c.families[d.name] = f
+		c.order = append(c.order, d.name)
+	}
+
+	var buf strings.Builder
+	buf.WriteString(d.name)
+	if len(labels) != 0 {
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i != 0 {
+				buf.WriteByte(',')
+			}
+			fmt.Fprintf(&buf, "%s=%s", labels[i], strconv.Quote(labels[i+1]))
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+
+	f.samples = append(f.samples, buf.String())
+}
+
+// merge appends all samples of o to c
+func (c *collection) merge(o *collection) {
+	for _, name := range o.order {
+		of := o.families[name]
+		f, ok := c.families[name]
+		if !ok {
+			f = &family{desc: of.desc}
+			c.families[name] = f
+			c.order = append(c.order, name)
+		}
+		f.samples = append(f.samples, of.samples...)
+	}
+}
+
+func (c *collection) write(w io.Writer) error {
+	for _, name := range c.order {
+		f := c.families[name]
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind); err != nil {
+			return err
+		}
+		for _, sample := range f.samples {
+			if _, err := fmt.Fprintln(w, sample); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// exporter periodically collects the state of the node modules and
+// serves the last collection over http
+type exporter struct {
+	network *stubs.NetworkerStub
+	storage *stubs.StorageModuleStub
+
+	m       sync.RWMutex
+	metrics []byte
+}
+
+func newExporter(cl zbus.Client) *exporter {
+	return &exporter{
+		network: stubs.NewNetworkerStub(cl),
+		storage: stubs.NewStorageModuleStub(cl),
+	}
+}
+
+func (e *exporter) run(ctx context.Context, interval time.Duration) {
+	for {
+		e.collect(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (e *exporter) collect(ctx context.Context) {
+	metrics := newCollection()
+	e.module(ctx, metrics, "network", e.collectNetwork)
+	e.module(ctx, metrics, "storage", e.collectStorage)
+
+	var buf bytes.Buffer
+	if err := metrics.write(&buf); err != nil {
+		log.Error().Err(err).Msg("failed to render metrics")
+		return
+	}
+
+	e.m.Lock()
+	defer e.m.Unlock()
+	e.metrics = buf.Bytes()
+}
+
+// module runs the collector of a single module. A module that is not
+// available is reported as down instead of failing the whole collection
+func (e *exporter) module(ctx context.Context, metrics *collection, name string, collector func(context.Context, *collection) error) {
+	ctx, cancel := context.WithTimeout(ctx, collectTimeout)
+	defer cancel()
+
+	// stubs panic if the module can't be reached
+	safe := func(c *collection) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = fmt.Errorf("collector has panicked: %v", p)
+			}
+		}()
+
+		return collector(ctx, c)
+	}
+
+	module := newCollection()
+	if err := safe(module); err != nil {
+		log.Error().Err(err).Str("module", name).Msg("failed to collect metrics")
+		metrics.add(moduleUp, 0, "module", name)
+		return
+	}
+
+	metrics.merge(module)
+	metrics.add(moduleUp, 1, "module", name)
+}
+
+func (e *exporter) collectNetwork(ctx context.Context, metrics *collection) error {
+	networks, err := e.network.ListNetworks(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range networks {
+		stats, err := e.network.NetworkStats(ctx, id)
+		if err != nil {
+			log.Error().Err(err).Str("network", id.String()).Msg("failed to get network stats")
+			continue
+		}
+
+		network := id.String()
+		metrics.add(networkRx, float64(stats.RxBytes), "network", network)
+		metrics.add(networkTx, float64(stats.TxBytes), "network", network)
+		metrics.add(networkPeers, float64(len(stats.Peers)), "network", network)
+
+		for _, peer := range stats.Peers {
+			metrics.add(peerRx, float64(peer.RxBytes), "network", network, "peer", peer.PublicKey)
+			metrics.add(peerTx, float64(peer.TxBytes), "network", network, "peer", peer.PublicKey)
+			if !peer.LastHandshake.IsZero() {
+				metrics.add(peerHandshake, float64(peer.LastHandshake.Unix()), "network", network, "peer", peer.PublicKey)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e *exporter) collectStorage(ctx context.Context, metrics *collection) error {
+	disks, err := e.storage.DiskList(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, disk := range disks {
+		metrics.add(vdiskSize, float64(disk.Size), "disk", disk.Name())
+		metrics.add(vdiskUsed, float64(disk.Used), "disk", disk.Name())
+	}
+
+	return nil
+}
+
+// ServeHTTP serves the last collected metrics
+func (e *exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write(e.metrics)
+}
@@ -0,0 +1,79 @@
+package exporterd
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/threefoldtech/zbus"
+	"github.com/threefoldtech/zos/pkg/environment"
+	"github.com/threefoldtech/zos/pkg/utils"
+	"github.com/urfave/cli/v2"
+)
+
+// Module is entry point for module
+var Module cli.Command = cli.Command{
+	Name:  "exporterd",
+	Usage: "exposes node network and storage state as prometheus metrics",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "broker",
+			Usage: "connection string to the message `BROKER`",
+			Value: environment.Broker(),
+		},
+		&cli.StringFlag{
+			Name:  "listen",
+			Usage: "`ADDRESS` to serve the /metrics endpoint on",
+			Value: "127.0.0.1:9190",
+		},
+		&cli.DurationFlag{
+			Name:  "interval",
+			Usage: "`DURATION` between two collections of the metrics",
+			Value: 30 * time.Second,
+		},
+	},
+	Action: action,
+}
+
+func action(cli *cli.Context) error {
+	var (
+		msgBrokerCon string        = cli.String("broker")
+		listen       string        = cli.String("listen")
+		interval     time.Duration = cli.Duration("interval")
+	)
+
+	cl, err := zbus.NewRedisClient(msgBrokerCon)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to message broker server")
+	}
+
+	ctx, _ := utils.WithSignal(cli.Context)
+	utils.OnDone(ctx, func(_ error) {
+		log.Info().Msg("shutting down")
+	})
+
+	exporter := newExporter(cl)
+	go exporter.run(ctx, interval)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exporter)
+
+	server := http.Server{
+		Addr:    listen,
+		Handler: mux,
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+
+	log.Info().Str("listen", listen).Msg("serving metrics")
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return errors.Wrap(err, "failed to serve metrics")
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/threefoldtech/zos/pkg/environment"
 	"github.com/threefoldtech/zos/pkg/stubs"
 	"github.com/threefoldtech/zos/pkg/utils"
 	"github.com/urfave/cli/v2"
@@ -35,7 +36,7 @@ var Module cli.Command = cli.Command{
 		&cli.StringFlag{
 			Name:  "broker",
 			Usage: "connection string to the message `BROKER`",
-			Value: "unix:///var/run/redis.sock",
+			Value: environment.Broker(),
 		},
 		&cli.UintFlag{
 			Name:  "workers",
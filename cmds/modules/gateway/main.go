@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/pkg/errors"
+	"github.com/threefoldtech/zos/pkg/environment"
 	"github.com/threefoldtech/zos/pkg/gateway"
 	"github.com/threefoldtech/zos/pkg/utils"
 	"github.com/urfave/cli/v2"
@@ -30,7 +31,7 @@ var Module cli.Command = cli.Command{
 		&cli.StringFlag{
 			Name:  "broker",
 			Usage: "connection string to the message `BROKER`",
-			Value: "unix:///var/run/redis.sock",
+			Value: environment.Broker(),
 		},
 		&cli.UintFlag{
 			Name:  "workers",
@@ -29,8 +29,7 @@ import (
 )
 
 const (
-	redisSocket = "unix:///var/run/redis.sock"
-	module      = "network"
+	module = "network"
 )
 
 // Module is entry point for module
@@ -46,7 +45,7 @@ var Module cli.Command = cli.Command{
 		&cli.StringFlag{
 			Name:  "broker",
 			Usage: "connection string to the message `BROKER`",
-			Value: "unix:///var/run/redis.sock",
+			Value: environment.Broker(),
 		},
 	},
 	Action: action,
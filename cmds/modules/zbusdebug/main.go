@@ -8,6 +8,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/threefoldtech/zbus"
+	"github.com/threefoldtech/zos/pkg/environment"
 	"github.com/urfave/cli/v2"
 	"gopkg.in/yaml.v2"
 
@@ -38,7 +39,7 @@ var (
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:  "broker",
-				Value: "unix:///var/run/redis.sock",
+				Value: environment.Broker(),
 				Usage: "connection string to the message `BROKER`",
 			},
 			&cli.StringFlag{
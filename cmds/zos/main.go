@@ -9,6 +9,7 @@ import (
 	"github.com/rs/zerolog/log"
 	apigateway "github.com/threefoldtech/zos/cmds/modules/api_gateway"
 	"github.com/threefoldtech/zos/cmds/modules/contd"
+	"github.com/threefoldtech/zos/cmds/modules/exporterd"
 	"github.com/threefoldtech/zos/cmds/modules/flistd"
 	"github.com/threefoldtech/zos/cmds/modules/gateway"
 	"github.com/threefoldtech/zos/cmds/modules/networkd"
@@ -58,6 +59,7 @@ func main() {
 			&qsfsd.Module,
 			&powerd.Module,
 			&apigateway.Module,
+			&exporterd.Module,
 		},
 		Before: func(c *cli.Context) error {
 			if c.Bool("debug") {
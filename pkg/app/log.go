@@ -60,6 +60,12 @@ func formatLevel(i interface{}) string {
 	return l
 }
 
+// log formats supported by SetLogFormat
+const (
+	LogFormatConsole = "console"
+	LogFormatJSON    = "json"
+)
+
 // Initialize Configure a zos app
 func Initialize() {
 	level := zerolog.InfoLevel
@@ -70,12 +76,46 @@ func Initialize() {
 
 	zerolog.SetGlobalLevel(level)
 
-	log.Logger = log.Output(zerolog.ConsoleWriter{
-		TimeFormat:  time.RFC3339,
-		Out:         os.Stdout,
-		FormatLevel: formatLevel,
-	})
+	// can't fail with a known format
+	_ = SetLogFormat(LogFormatConsole)
+}
+
+// SetLogFormat switches the output format of the global logger. The
+// console format is human readable (default), the json format writes
+// one json object per line to make the logs easy to ingest.
+func SetLogFormat(format string) error {
+	switch format {
+	case LogFormatConsole:
+		log.Logger = log.Output(zerolog.ConsoleWriter{
+			TimeFormat:  time.RFC3339,
+			Out:         os.Stdout,
+			FormatLevel: formatLevel,
+		})
+	case LogFormatJSON:
+		log.Logger = log.Output(os.Stdout)
+	default:
+		return fmt.Errorf("unknown log format '%s' expected %s or %s", format, LogFormatConsole, LogFormatJSON)
+	}
+
+	return nil
+}
+
+// SetLogLevel sets the global log level. Accepted levels are
+// debug, info, warn and error.
+func SetLogLevel(level string) error {
+	switch level {
+	case "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("unknown log level '%s' expected debug, info, warn or error", level)
+	}
+
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return err
+	}
 
+	zerolog.SetGlobalLevel(lvl)
+	return nil
 }
 
 // SampledLogger return a sampled logger that allow 1 log entry per hour
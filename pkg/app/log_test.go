@@ -0,0 +1,25 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLogFormat(t *testing.T) {
+	require.NoError(t, SetLogFormat(LogFormatJSON))
+	require.NoError(t, SetLogFormat(LogFormatConsole))
+	require.Error(t, SetLogFormat("xml"))
+}
+
+func TestSetLogLevel(t *testing.T) {
+	defer zerolog.SetGlobalLevel(zerolog.GlobalLevel())
+
+	require.NoError(t, SetLogLevel("debug"))
+	require.Equal(t, zerolog.DebugLevel, zerolog.GlobalLevel())
+	require.NoError(t, SetLogLevel("warn"))
+	require.Equal(t, zerolog.WarnLevel, zerolog.GlobalLevel())
+	require.Error(t, SetLogLevel("trace"))
+	require.Error(t, SetLogLevel("verbose"))
+}
@@ -212,6 +212,20 @@ func MustGet() Environment {
 	return env
 }
 
+// DefaultBroker is the default connection string of the message broker
+const DefaultBroker = "unix:///var/run/redis.sock"
+
+// Broker returns the connection string of the message broker. It can be
+// overridden with the ZOS_BROKER environment variable. Daemons use it as
+// the default value of their broker flag so an explicit flag always wins
+func Broker() string {
+	if e := os.Getenv("ZOS_BROKER"); e != "" {
+		return e
+	}
+
+	return DefaultBroker
+}
+
 // Get return the running environment of the node
 func Get() (Environment, error) {
 	params := kernel.GetParams()
@@ -3,8 +3,10 @@ package zos
 import (
 	"bytes"
 	"crypto/md5"
+	"encoding/base64"
 	"fmt"
 	"io"
+	"net"
 
 	"github.com/jbenet/go-base58"
 	"github.com/threefoldtech/zos/pkg/gridtypes"
@@ -12,6 +14,8 @@ import (
 
 const (
 	MyceliumKeyLen = 32
+	// WGKeyLen is the length of a wireguard key (private or public)
+	WGKeyLen = 32
 )
 
 // NetID is a type defining the ID of a network
@@ -134,18 +138,44 @@ func (n Network) Valid(getter gridtypes.WorkloadGetter) error {
 		return fmt.Errorf("network IP range cannot be empty")
 	}
 
+	if n.NetworkIPRange.IP.To4() == nil {
+		return fmt.Errorf("network IP range must be an IPv4 range")
+	}
+
 	if len(n.Subnet.IP) == 0 {
 		return fmt.Errorf("network resource subnet cannot empty")
 	}
 
+	if !isSubnetOf(n.NetworkIPRange.IPNet, n.Subnet.IPNet) {
+		return fmt.Errorf("network resource subnet '%s' is not part of network IP range '%s'", n.Subnet.String(), n.NetworkIPRange.String())
+	}
+
 	if n.WGPrivateKey == "" {
 		return fmt.Errorf("network resource wireguard private key cannot empty")
 	}
 
+	if err := validWGKey(n.WGPrivateKey); err != nil {
+		return fmt.Errorf("invalid network resource wireguard private key: %w", err)
+	}
+
+	subnets := map[string]struct{}{
+		n.Subnet.String(): {},
+	}
+	keys := make(map[string]struct{})
 	for _, peer := range n.Peers {
 		if err := peer.Valid(); err != nil {
 			return err
 		}
+
+		if _, ok := keys[peer.WGPublicKey]; ok {
+			return fmt.Errorf("duplicate peer wireguard public key '%s'", peer.WGPublicKey)
+		}
+		keys[peer.WGPublicKey] = struct{}{}
+
+		if _, ok := subnets[peer.Subnet.String()]; ok {
+			return fmt.Errorf("peer subnet '%s' is used more than once in the network", peer.Subnet.String())
+		}
+		subnets[peer.Subnet.String()] = struct{}{}
 	}
 
 	if n.Mycelium != nil {
@@ -157,6 +187,28 @@ func (n Network) Valid(getter gridtypes.WorkloadGetter) error {
 	return nil
 }
 
+// isSubnetOf checks if sub is fully contained in network
+func isSubnetOf(network, sub net.IPNet) bool {
+	netSize, _ := network.Mask.Size()
+	subSize, _ := sub.Mask.Size()
+
+	return subSize >= netSize && network.Contains(sub.IP)
+}
+
+// validWGKey checks that key is a base64 encoded wireguard key
+func validWGKey(key string) error {
+	data, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return fmt.Errorf("key is not base64 encoded")
+	}
+
+	if len(data) != WGKeyLen {
+		return fmt.Errorf("invalid key length, expected %d", WGKeyLen)
+	}
+
+	return nil
+}
+
 // Challenge implements WorkloadData
 func (n Network) Challenge(b io.Writer) error {
 	if _, err := fmt.Fprintf(b, "%s", n.NetworkIPRange.String()); err != nil {
@@ -222,6 +274,10 @@ func (p *Peer) Valid() error {
 		return fmt.Errorf("peer wireguard public key cannot empty")
 	}
 
+	if err := validWGKey(p.WGPublicKey); err != nil {
+		return fmt.Errorf("invalid peer wireguard public key: %w", err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,73 @@
+package zos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/threefoldtech/zos/pkg/gridtypes"
+)
+
+func TestNetworkValid(t *testing.T) {
+	const (
+		key1 = "GDU+cjKrHNJS9fodzjFDzNFl5su3kJXTZ3ipPgUjOUE="
+		key2 = "4w4woC+AuDUAaRipT49M8SmTkzERps3xA5i0BW4XPns="
+	)
+
+	valid := func() Network {
+		return Network{
+			NetworkIPRange: gridtypes.MustParseIPNet("10.1.0.0/16"),
+			Subnet:         gridtypes.MustParseIPNet("10.1.1.0/24"),
+			WGPrivateKey:   key1,
+			WGListenPort:   6380,
+			Peers: []Peer{
+				{
+					Subnet:      gridtypes.MustParseIPNet("10.1.2.0/24"),
+					WGPublicKey: key2,
+					AllowedIPs: []gridtypes.IPNet{
+						gridtypes.MustParseIPNet("10.1.2.0/24"),
+						gridtypes.MustParseIPNet("100.64.1.2/32"),
+					},
+				},
+			},
+		}
+	}
+
+	require.NoError(t, valid().Valid(nil))
+
+	cases := map[string]func(n *Network){
+		"ipv6 range": func(n *Network) {
+			n.NetworkIPRange = gridtypes.MustParseIPNet("fd00::/64")
+		},
+		"subnet outside range": func(n *Network) {
+			n.Subnet = gridtypes.MustParseIPNet("10.2.1.0/24")
+		},
+		"subnet larger than range": func(n *Network) {
+			n.Subnet = gridtypes.MustParseIPNet("10.0.0.0/8")
+		},
+		"invalid private key": func(n *Network) {
+			n.WGPrivateKey = "not a key"
+		},
+		"short private key": func(n *Network) {
+			n.WGPrivateKey = "aGVsbG8="
+		},
+		"invalid peer key": func(n *Network) {
+			n.Peers[0].WGPublicKey = "not a key"
+		},
+		"duplicate peer key": func(n *Network) {
+			peer := n.Peers[0]
+			peer.Subnet = gridtypes.MustParseIPNet("10.1.3.0/24")
+			n.Peers = append(n.Peers, peer)
+		},
+		"peer uses own subnet": func(n *Network) {
+			n.Peers[0].Subnet = n.Subnet
+		},
+	}
+
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			network := valid()
+			mutate(&network)
+			require.Error(t, network.Valid(nil))
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package identity
 
 import (
+	"crypto/ed25519"
 	"fmt"
 	"path/filepath"
 
@@ -24,51 +25,76 @@ const (
 // exits, this file key will be migrated to the TPM store then
 // deleted (only if delete is set to true)
 func NewStore(root string, delete bool) (store.Store, error) {
-	file := store.NewFileStore(filepath.Join(root, seedName))
-	if disableTpm || !store.IsTPMEnabled() {
-		return file, nil
-	}
-
-	// tpm is supported, but do we have a key
-	tpm := store.NewTPM()
-	exists, err := file.Exists()
-	if err != nil {
-		return nil, fmt.Errorf("failed to check for seed file: %s", err)
-	}
-
-	if !exists {
-		return tpm, nil
-	}
-
-	if ok, err := tpm.Exists(); err == nil && ok {
-		// so there is a key on disk, but tpm already has a stored key
-		// then we still just return no need for migration to avoid
-		// overriding the key in tpm
-		return tpm, nil
+	st, migrate, err := selectStore(root)
+	if err != nil || !migrate {
+		return st, err
 	}
 
 	// if we failed to get the key from store
 	// may be better generate a new one?
 	// todo: need discussion
 
-	key, err := file.Get()
+	key, err := st.Get()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load key from file: %w", err)
 	}
 
 	// migration of key
+	tpm := store.NewTPM()
 	if err := tpm.Set(key); err != nil {
 		// we failed to do migration but we have a valid key.
 		// we shouldn't then fail instead use the file store
 		log.Error().Err(err).Msg("failed to migrate key to tpm store")
-		return file, nil
+		return st, nil
 	}
 
 	if delete {
-		if err := file.Annihilate(); err != nil {
+		if err := st.Annihilate(); err != nil {
 			log.Error().Err(err).Msg("failed to clear up key file")
 		}
 	}
 
 	return tpm, nil
 }
+
+// LoadKey loads the node key from the same store NewStore uses, but
+// without changing anything: no key is generated if there is none and
+// a seed file is not migrated to the tpm
+func LoadKey(root string) (ed25519.PrivateKey, error) {
+	st, _, err := selectStore(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return st.Get()
+}
+
+// selectStore picks the key store of the node. migrate is set if the
+// key is still in the seed file and needs to be moved to the tpm, in
+// that case the returned store is the file store
+func selectStore(root string) (st store.Store, migrate bool, err error) {
+	file := store.NewFileStore(filepath.Join(root, seedName))
+	if disableTpm || !store.IsTPMEnabled() {
+		return file, false, nil
+	}
+
+	// tpm is supported, but do we have a key
+	tpm := store.NewTPM()
+	exists, err := file.Exists()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check for seed file: %s", err)
+	}
+
+	if !exists {
+		return tpm, false, nil
+	}
+
+	if ok, err := tpm.Exists(); err == nil && ok {
+		// so there is a key on disk, but tpm already has a stored key
+		// then we still just return no need for migration to avoid
+		// overriding the key in tpm
+		return tpm, false, nil
+	}
+
+	return file, true, nil
+}
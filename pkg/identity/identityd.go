@@ -7,12 +7,16 @@ import (
 	substrate "github.com/threefoldtech/tfchain/clients/tfchain-client-go"
 	"github.com/threefoldtech/zos/pkg/crypto"
 	"github.com/threefoldtech/zos/pkg/identity/store"
+	"golang.org/x/crypto/ed25519"
 
 	"github.com/pkg/errors"
 	"github.com/threefoldtech/zos/pkg"
 	"github.com/threefoldtech/zos/pkg/environment"
 )
 
+// seedKind is the store kind of managers created from a seed
+const seedKind = "seed"
+
 type identityManager struct {
 	kind string
 	key  KeyPair
@@ -55,6 +59,22 @@ func NewManager(root string, debug bool) (pkg.IdentityManager, error) {
 		pair = KeyPairFromKey(key)
 	}
 
+	return newManager(st.Kind(), pair)
+}
+
+// NewManagerFromSeed creates an identity manager from an ed25519 seed.
+// The key is only kept in memory, so multiple managers with different
+// seeds can be used in the same process (for example to simulate several
+// nodes in tests)
+func NewManagerFromSeed(seed []byte) (pkg.IdentityManager, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid seed length %d expected %d", len(seed), ed25519.SeedSize)
+	}
+
+	return newManager(seedKind, KeyPairFromKey(ed25519.NewKeyFromSeed(seed)))
+}
+
+func newManager(kind string, pair KeyPair) (*identityManager, error) {
 	sub, err := environment.GetSubstrate()
 	if err != nil {
 		return nil, err
@@ -65,7 +85,7 @@ func NewManager(root string, debug bool) (pkg.IdentityManager, error) {
 	}
 
 	return &identityManager{
-		kind: st.Kind(),
+		kind: kind,
 		key:  pair,
 		sub:  sub,
 		env:  env,
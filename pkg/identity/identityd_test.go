@@ -0,0 +1,41 @@
+package identity
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/threefoldtech/zos/pkg/identity/store"
+)
+
+func TestNewManagerFromSeed(t *testing.T) {
+	m1, err := NewManagerFromSeed([]byte("helloworldhelloworldhelloworld12"))
+	require.NoError(t, err)
+	m2, err := NewManagerFromSeed([]byte("helloworldhelloworldhelloworld34"))
+	require.NoError(t, err)
+
+	require.Equal(t, "FkUfMueBVSK6V1DCHVAtzzaqPqCPVzGguDzCQxq7Ep85", m1.NodeID().Identity())
+	require.NotEqual(t, m1.NodeID().Identity(), m2.NodeID().Identity())
+	require.Equal(t, seedKind, m1.StoreKind())
+
+	_, err = NewManagerFromSeed([]byte("short"))
+	require.Error(t, err)
+}
+
+func TestLoadKey(t *testing.T) {
+	root := t.TempDir()
+
+	// no key is generated
+	_, err := LoadKey(root)
+	require.ErrorIs(t, err, store.ErrKeyDoesNotExist)
+	entries, err := os.ReadDir(root)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	mgr, err := NewManager(root, false)
+	require.NoError(t, err)
+
+	key, err := LoadKey(root)
+	require.NoError(t, err)
+	require.Equal(t, mgr.NodeID().Identity(), KeyPairFromKey(key).Identity())
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"time"
 
 	substrate "github.com/threefoldtech/tfchain/clients/tfchain-client-go"
 	"github.com/threefoldtech/zos/pkg/gridtypes"
@@ -59,6 +60,44 @@ func (e *ExitDevice) String() string {
 
 type NetResourceMetrics map[string]NetMetric
 
+// WGPeerStatus is the status of a wireguard peer of a network resource
+type WGPeerStatus struct {
+	PublicKey string `json:"public_key"`
+	Endpoint  string `json:"endpoint"`
+	// LastHandshake is zero if the peer never completed a handshake
+	LastHandshake time.Time `json:"last_handshake"`
+	RxBytes       uint64    `json:"rx_bytes"`
+	TxBytes       uint64    `json:"tx_bytes"`
+}
+
+// WGPeerConfig is the configuration of a wireguard peer
+type WGPeerConfig struct {
+	PublicKey           string        `json:"public_key"`
+	Endpoint            string        `json:"endpoint"`
+	AllowedIPs          []string      `json:"allowed_ips"`
+	PersistentKeepalive time.Duration `json:"persistent_keepalive"`
+}
+
+// WGConfig is the live configuration of the wireguard interface of
+// a network resource. The private key is never included
+type WGConfig struct {
+	PublicKey  string         `json:"public_key"`
+	ListenPort int            `json:"listen_port"`
+	Addresses  []string       `json:"addresses"`
+	Peers      []WGPeerConfig `json:"peers"`
+}
+
+// WGNetworkStats are the traffic counters of the wireguard interface
+// of a network resource
+type WGNetworkStats struct {
+	// RxBytes and TxBytes are the counters of the interface itself
+	RxBytes uint64 `json:"rx_bytes"`
+	TxBytes uint64 `json:"tx_bytes"`
+	// Peers holds the per peer counters, which count the encrypted
+	// traffic and don't add up to the interface counters
+	Peers []WGPeerStatus `json:"peers"`
+}
+
 // Networker is the interface for the network module
 type Networker interface {
 	// Ready return nil is networkd is ready to operate
@@ -70,9 +109,30 @@ type Networker interface {
 	// Delete a network resource
 	DeleteNR(wl gridtypes.WorkloadID) error
 
+	// ListNetworks returns the IDs of all network resources that
+	// are deployed on this node
+	ListNetworks() ([]NetID, error)
+
+	// PeerStatus returns the status of the wireguard peers of
+	// the given network
+	PeerStatus(id NetID) ([]WGPeerStatus, error)
+	// NetworkStats returns the total and per peer traffic of the
+	// wireguard interface of the given network
+	NetworkStats(id NetID) (WGNetworkStats, error)
+	// WGConfig returns the configuration applied to the wireguard
+	// interface of the given network
+	WGConfig(id NetID) (WGConfig, error)
+	// Ping sends an icmp echo request to target from inside the
+	// given network and returns the round trip time of the reply
+	Ping(id NetID, target string) (time.Duration, error)
+
 	// Namespace returns the namespace name for given netid.
 	// it doesn't check if network exists.
 	Namespace(id zos.NetID) string
+	// NamespacePath returns the path of the namespace of the given
+	// network (for example /var/run/netns/n-<id>). It fails if the
+	// network is not deployed on this node.
+	NamespacePath(id zos.NetID) (string, error)
 	// deprecated all uses taps now
 
 	// // Join a network (with network id) will create a new isolated namespace
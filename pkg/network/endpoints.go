@@ -0,0 +1,183 @@
+package network
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/threefoldtech/zos/pkg"
+	"github.com/threefoldtech/zos/pkg/gridtypes/zos"
+	"github.com/threefoldtech/zos/pkg/network/nr"
+	"github.com/threefoldtech/zos/pkg/network/wireguard"
+)
+
+const (
+	// endpointsRefresh is how often peer endpoints that use host
+	// names are resolved again
+	endpointsRefresh = 5 * time.Minute
+
+	// resolveTimeout bounds the time spent resolving the host name
+	// endpoints of all the peers of a network
+	resolveTimeout = 10 * time.Second
+)
+
+// resolvedEndpoints remembers, per network, the address each host name
+// endpoint was last applied with. The live endpoint of a peer can't be
+// used for this since wireguard updates it when the peer roams
+type resolvedEndpoints struct {
+	m     sync.Mutex
+	addrs map[pkg.NetID]map[string]string
+}
+
+func newResolvedEndpoints() *resolvedEndpoints {
+	return &resolvedEndpoints{addrs: make(map[pkg.NetID]map[string]string)}
+}
+
+func (r *resolvedEndpoints) get(id pkg.NetID, peer string) (string, bool) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	addr, ok := r.addrs[id][peer]
+	return addr, ok
+}
+
+func (r *resolvedEndpoints) set(id pkg.NetID, peer, addr string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.addrs[id] == nil {
+		r.addrs[id] = make(map[string]string)
+	}
+	r.addrs[id][peer] = addr
+}
+
+// reset replaces all the addresses of network id
+func (r *resolvedEndpoints) reset(id pkg.NetID, addrs map[string]string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.addrs[id] = addrs
+}
+
+func (r *resolvedEndpoints) delete(id pkg.NetID) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	delete(r.addrs, id)
+}
+
+// resolvePeers resolves the host name endpoints of peers from the host
+// namespace, and returns the address of each of them by peer public key.
+// A name that can't be resolved is left out rather than failing the whole
+// network, the peer gets its endpoint once the name resolves again
+func resolvePeers(id pkg.NetID, peers []zos.Peer) map[string]string {
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+
+	resolved := make(map[string]string)
+	for _, peer := range peers {
+		if !wireguard.HasHostname(peer.Endpoint) {
+			continue
+		}
+
+		addr, err := wireguard.ResolveEndpoint(ctx, peer.Endpoint)
+		if err != nil {
+			log.Warn().Err(err).Str("network", id.String()).Str("peer", peer.WGPublicKey).Msg("failed to resolve peer endpoint")
+			continue
+		}
+
+		resolved[peer.WGPublicKey] = addr.String()
+	}
+
+	return resolved
+}
+
+// watchEndpoints keeps wireguard peers that have host name endpoints
+// pointing to the current address of the host. Otherwise the tunnel
+// stays pinned to the address the name had when the network was created
+func (n *networker) watchEndpoints() {
+	ticker := time.NewTicker(endpointsRefresh)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		networks, err := n.ListNetworks()
+		if err != nil {
+			log.Error().Err(err).Msg("failed to list networks to refresh peer endpoints")
+			continue
+		}
+
+		for _, id := range networks {
+			if err := n.refreshEndpoints(id); err != nil {
+				log.Error().Err(err).Str("network", id.String()).Msg("failed to refresh peer endpoints")
+			}
+		}
+	}
+}
+
+// refreshEndpoints resolves the host name endpoints of network id again and
+// updates, in place, only the peers whose name now resolves to a different
+// address than the one last applied. Names are resolved without holding
+// the network lock
+func (n *networker) refreshEndpoints(id pkg.NetID) error {
+	unlock := n.locks.lock(id)
+	netNR, err := n.networkOf(id)
+	unlock()
+	if err != nil {
+		return errors.Wrap(err, "failed to load network")
+	}
+
+	names := make(map[string]string)
+	for _, peer := range netNR.Peers {
+		names[peer.WGPublicKey] = peer.Endpoint
+	}
+
+	resolved := resolvePeers(id, netNR.Peers)
+	if len(resolved) == 0 {
+		return nil
+	}
+
+	defer n.locks.lock(id)()
+
+	// the network can be updated while its peers are resolved
+	netNR, err = n.networkOf(id)
+	if err != nil {
+		return errors.Wrap(err, "failed to load network")
+	}
+
+	netr := nr.New(netNR, n.myceliumKeyDir)
+	for _, peer := range netNR.Peers {
+		addr, ok := resolved[peer.WGPublicKey]
+		if !ok || names[peer.WGPublicKey] != peer.Endpoint {
+			continue
+		}
+
+		last, ok := n.endpoints.get(id, peer.WGPublicKey)
+		if ok && last == addr {
+			continue
+		}
+
+		log.Info().
+			Str("network", id.String()).
+			Str("endpoint", peer.Endpoint).
+			Str("old", last).
+			Str("new", addr).
+			Msg("peer endpoint address changed")
+
+		udpAddr, err := net.ResolveUDPAddr("udp", addr)
+		if err != nil {
+			return err
+		}
+
+		if err := netr.SetPeerEndpoint(peer.WGPublicKey, udpAddr); err != nil {
+			log.Error().Err(err).Str("network", id.String()).Msg("failed to update peer endpoint")
+			continue
+		}
+
+		n.endpoints.set(id, peer.WGPublicKey, addr)
+	}
+
+	return nil
+}
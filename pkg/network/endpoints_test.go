@@ -0,0 +1,46 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/threefoldtech/zos/pkg"
+	"github.com/threefoldtech/zos/pkg/gridtypes/zos"
+)
+
+func TestResolvedEndpoints(t *testing.T) {
+	r := newResolvedEndpoints()
+	id := pkg.NetID("net1")
+
+	_, ok := r.get(id, "peer")
+	require.False(t, ok)
+
+	r.reset(id, map[string]string{"peer": "1.2.3.4:51820"})
+	addr, ok := r.get(id, "peer")
+	require.True(t, ok)
+	require.Equal(t, "1.2.3.4:51820", addr)
+
+	r.set(id, "peer", "5.6.7.8:51820")
+	addr, _ = r.get(id, "peer")
+	require.Equal(t, "5.6.7.8:51820", addr)
+
+	r.delete(id)
+	_, ok = r.get(id, "peer")
+	require.False(t, ok)
+
+	// set works for networks that were never reset
+	r.set(id, "peer", "1.2.3.4:51820")
+	_, ok = r.get(id, "peer")
+	require.True(t, ok)
+}
+
+func TestResolvePeers(t *testing.T) {
+	resolved := resolvePeers("net1", []zos.Peer{
+		{WGPublicKey: "numeric", Endpoint: "1.2.3.4:51820"},
+		{WGPublicKey: "none"},
+		{WGPublicKey: "name", Endpoint: "localhost:51820"},
+	})
+
+	require.Len(t, resolved, 1)
+	require.Contains(t, resolved, "name")
+}
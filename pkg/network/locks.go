@@ -0,0 +1,49 @@
+package network
+
+import (
+	"sync"
+
+	"github.com/threefoldtech/zos/pkg"
+)
+
+// netLocks serializes operations on the same network. Operations on
+// different networks can still run in parallel
+type netLocks struct {
+	m     sync.Mutex
+	locks map[pkg.NetID]*netLock
+}
+
+type netLock struct {
+	sync.Mutex
+	refs int
+}
+
+func newNetLocks() *netLocks {
+	return &netLocks{locks: make(map[pkg.NetID]*netLock)}
+}
+
+// lock blocks until the lock of network id is acquired. The returned
+// function must be called to release it
+func (l *netLocks) lock(id pkg.NetID) (unlock func()) {
+	l.m.Lock()
+	lock, ok := l.locks[id]
+	if !ok {
+		lock = &netLock{}
+		l.locks[id] = lock
+	}
+	lock.refs++
+	l.m.Unlock()
+
+	lock.Lock()
+
+	return func() {
+		lock.Unlock()
+
+		l.m.Lock()
+		defer l.m.Unlock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(l.locks, id)
+		}
+	}
+}
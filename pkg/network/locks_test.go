@@ -0,0 +1,55 @@
+package network
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetLocksSameNetwork(t *testing.T) {
+	locks := newNetLocks()
+
+	var active, max int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := locks.lock("net")
+			defer unlock()
+
+			current := atomic.AddInt32(&active, 1)
+			if current > atomic.LoadInt32(&max) {
+				atomic.StoreInt32(&max, current)
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&active, -1)
+		}()
+	}
+
+	wg.Wait()
+	require.EqualValues(t, 1, max)
+	require.Empty(t, locks.locks)
+}
+
+func TestNetLocksDifferentNetworks(t *testing.T) {
+	locks := newNetLocks()
+
+	unlock := locks.lock("net-1")
+	defer unlock()
+
+	done := make(chan struct{})
+	go func() {
+		locks.lock("net-2")()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lock of a different network was blocked")
+	}
+}
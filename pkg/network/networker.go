@@ -82,6 +82,10 @@ type networker struct {
 	ipamLeaseDir   string
 	myceliumKeyDir string
 	portSet        *set.UIntSet
+	// locks serializes create and delete of the same network
+	locks *netLocks
+	// endpoints are the last applied addresses of host name endpoints
+	endpoints *resolvedEndpoints
 
 	ndmz     ndmz.DMZ
 	ygg      *yggdrasil.YggServer
@@ -115,6 +119,8 @@ func NewNetworker(identity *stubs.IdentityManagerStub, ndmz ndmz.DMZ, ygg *yggdr
 		ipamLeaseDir:   ipamLease,
 		myceliumKeyDir: myceliumKey,
 		portSet:        set.NewInt(),
+		locks:          newNetLocks(),
+		endpoints:      newResolvedEndpoints(),
 
 		ygg:      ygg,
 		mycelium: myc,
@@ -134,6 +140,8 @@ func NewNetworker(identity *stubs.IdentityManagerStub, ndmz ndmz.DMZ, ygg *yggdr
 		return nil, err
 	}
 
+	go nw.watchEndpoints()
+
 	return nw, nil
 }
 
@@ -926,25 +934,43 @@ func (n *networker) Addrs(iface string, netns string) (ips []net.IP, mac string,
 func (n *networker) CreateNR(wl gridtypes.WorkloadID, netNR pkg.Network) (string, error) {
 	log.Info().Str("network", string(netNR.NetID)).Msg("create network resource")
 
-	if err := n.storeNetwork(wl, netNR); err != nil {
-		return "", errors.Wrap(err, "failed to store network object")
-	}
+	// lookups can be slow, so host name endpoints are resolved
+	// before the network is locked
+	endpoints := resolvePeers(netNR.NetID, netNR.Peers)
+
+	defer n.locks.lock(netNR.NetID)()
 
 	// check if there is a reserved wireguard port for this NR already
-	// or if we need to update it
+	// or if we need to update it. this need to happen before the new
+	// network object is stored, otherwise we will read the new port
+	// and the old one is never released
 	storedNR, err := n.networkOf(netNR.NetID)
 	if err != nil && !os.IsNotExist(err) {
 		return "", errors.Wrap(err, "failed to load previous network setup")
 	}
+	stored := err == nil
 
-	if err == nil {
-		if err := n.releasePort(storedNR.WGListenPort); err != nil {
+	// the new port is reserved first, so nothing has changed yet if it
+	// is taken. the old port is only released once the new network is
+	// stored
+	changed := !stored || storedNR.WGListenPort != netNR.WGListenPort
+	if changed {
+		if err := n.reservePort(netNR.WGListenPort); err != nil {
 			return "", err
 		}
 	}
 
-	if err := n.reservePort(netNR.WGListenPort); err != nil {
-		return "", err
+	if err := n.storeNetwork(wl, netNR); err != nil {
+		if changed {
+			_ = n.releasePort(netNR.WGListenPort)
+		}
+		return "", errors.Wrap(err, "failed to store network object")
+	}
+
+	if stored && changed {
+		if err := n.releasePort(storedNR.WGListenPort); err != nil {
+			return "", err
+		}
 	}
 
 	netr := nr.New(netNR, n.myceliumKeyDir)
@@ -976,7 +1002,7 @@ func (n *networker) CreateNR(wl gridtypes.WorkloadID, netNR pkg.Network) (string
 	}
 
 	// setup mycelium
-	if err := netr.SetMycelium(); err != nil {
+	if err = netr.SetMycelium(); err != nil {
 		return "", errors.Wrap(err, "failed to setup mycelium")
 	}
 
@@ -1005,10 +1031,12 @@ func (n *networker) CreateNR(wl gridtypes.WorkloadID, netNR pkg.Network) (string
 		return "", errors.Wrapf(err, "failed to attach network resource to DMZ bridge")
 	}
 
-	if err = netr.ConfigureWG(netNR.WGPrivateKey); err != nil {
+	if err = netr.ConfigureWG(netNR.WGPrivateKey, endpoints); err != nil {
 		return "", errors.Wrap(err, "failed to configure network resource")
 	}
 
+	n.endpoints.reset(netNR.NetID, endpoints)
+
 	return netr.Namespace()
 }
 
@@ -1063,6 +1091,9 @@ func (n *networker) DeleteNR(wl gridtypes.WorkloadID) error {
 	if err != nil {
 		return err
 	}
+
+	defer n.locks.lock(netID)()
+
 	netNR, err := n.networkOf(netID)
 	if err != nil {
 		return err
@@ -1087,13 +1118,150 @@ func (n *networker) DeleteNR(wl gridtypes.WorkloadID) error {
 		log.Error().Err(err).Msg("failed to remove file mapping between network ID and namespace")
 	}
 
+	n.endpoints.delete(netID)
+
 	return nil
 }
 
+// ListNetworks implements pkg.Networker interface
+func (n *networker) ListNetworks() ([]pkg.NetID, error) {
+	entries, err := os.ReadDir(n.networkDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list networks")
+	}
+
+	var networks []pkg.NetID
+	for _, entry := range entries {
+		// skip the link directory
+		if entry.IsDir() {
+			continue
+		}
+
+		networks = append(networks, pkg.NetID(entry.Name()))
+	}
+
+	return networks, nil
+}
+
+// PeerStatus implements pkg.Networker interface
+func (n *networker) PeerStatus(id pkg.NetID) ([]pkg.WGPeerStatus, error) {
+	netNR, err := n.networkOf(id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load network %s", id)
+	}
+
+	device, err := nr.New(netNR, n.myceliumKeyDir).WGDevice()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get wireguard device")
+	}
+
+	peers := make([]pkg.WGPeerStatus, 0, len(device.Peers))
+	for _, peer := range device.Peers {
+		status := pkg.WGPeerStatus{
+			PublicKey:     peer.PublicKey.String(),
+			LastHandshake: peer.LastHandshakeTime,
+			RxBytes:       uint64(peer.ReceiveBytes),
+			TxBytes:       uint64(peer.TransmitBytes),
+		}
+
+		if peer.Endpoint != nil {
+			status.Endpoint = peer.Endpoint.String()
+		}
+
+		peers = append(peers, status)
+	}
+
+	return peers, nil
+}
+
+// NetworkStats implements pkg.Networker interface
+func (n *networker) NetworkStats(id pkg.NetID) (stats pkg.WGNetworkStats, err error) {
+	netNR, err := n.networkOf(id)
+	if err != nil {
+		return stats, errors.Wrapf(err, "failed to load network %s", id)
+	}
+
+	// the interface counters count the inner packets, while the peer
+	// counters count the encrypted traffic including handshakes and
+	// keep alives, so they are reported separately
+	link, err := nr.New(netNR, n.myceliumKeyDir).WGStatistics()
+	if err != nil {
+		return stats, errors.Wrap(err, "failed to get wireguard interface statistics")
+	}
+
+	stats.RxBytes = link.RxBytes
+	stats.TxBytes = link.TxBytes
+
+	stats.Peers, err = n.PeerStatus(id)
+	if err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// WGConfig implements pkg.Networker interface
+func (n *networker) WGConfig(id pkg.NetID) (cfg pkg.WGConfig, err error) {
+	netNR, err := n.networkOf(id)
+	if err != nil {
+		return cfg, errors.Wrapf(err, "failed to load network %s", id)
+	}
+
+	netr := nr.New(netNR, n.myceliumKeyDir)
+	device, err := netr.WGDevice()
+	if err != nil {
+		return cfg, errors.Wrap(err, "failed to get wireguard device")
+	}
+
+	addrs, err := netr.WGAddrs()
+	if err != nil {
+		return cfg, errors.Wrap(err, "failed to get wireguard addresses")
+	}
+
+	// only the public part of the device key is exposed
+	cfg.PublicKey = device.PublicKey.String()
+	cfg.ListenPort = device.ListenPort
+	for _, addr := range addrs {
+		cfg.Addresses = append(cfg.Addresses, addr.IPNet.String())
+	}
+
+	for _, peer := range device.Peers {
+		peerCfg := pkg.WGPeerConfig{
+			PublicKey:           peer.PublicKey.String(),
+			PersistentKeepalive: peer.PersistentKeepaliveInterval,
+		}
+
+		if peer.Endpoint != nil {
+			peerCfg.Endpoint = peer.Endpoint.String()
+		}
+
+		for _, ip := range peer.AllowedIPs {
+			peerCfg.AllowedIPs = append(peerCfg.AllowedIPs, ip.String())
+		}
+
+		cfg.Peers = append(cfg.Peers, peerCfg)
+	}
+
+	return cfg, nil
+}
+
 func (n *networker) Namespace(id zos.NetID) string {
 	return fmt.Sprintf("n-%s", id)
 }
 
+// NamespacePath implements pkg.Networker interface
+func (n *networker) NamespacePath(id zos.NetID) (string, error) {
+	netNS, err := namespace.GetByName(n.Namespace(id))
+	if os.IsNotExist(err) {
+		return "", errors.Errorf("network %s is not deployed on this node", id)
+	} else if err != nil {
+		return "", errors.Wrapf(err, "failed to get namespace of network %s", id)
+	}
+	defer netNS.Close()
+
+	return netNS.Path(), nil
+}
+
 func (n *networker) UnsetPublicConfig() error {
 	id := n.identity.NodeID(context.Background())
 	_, err := public.EnsurePublicSetup(id, environment.MustGet().PubVlan, nil)
@@ -33,6 +33,7 @@ import (
 	"github.com/threefoldtech/zos/pkg/network/nft"
 	"github.com/threefoldtech/zos/pkg/network/wireguard"
 	"github.com/vishvananda/netlink"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 )
 
 const (
@@ -477,9 +478,11 @@ func wgIP(subnet *net.IPNet) *net.IPNet {
 }
 
 // ConfigureWG sets the routes and IP addresses on the
-// wireguard interface of the network resources
-func (nr *NetResource) ConfigureWG(privateKey string) error {
-	wgPeers, err := nr.wgPeers()
+// wireguard interface of the network resources. endpoints maps the public
+// key of peers with a host name endpoint to the address it resolved to,
+// a peer missing from it is configured without an endpoint
+func (nr *NetResource) ConfigureWG(privateKey string, endpoints map[string]string) error {
+	wgPeers, err := nr.wgPeers(endpoints)
 	if err != nil {
 		return errors.Wrap(err, "failed to wireguard peer configuration")
 	}
@@ -590,9 +593,14 @@ func (nr *NetResource) Delete() error {
 
 		_ = init.Forget(myceliumName)
 		_ = zinit.RemoveService(myceliumName)
+	}
 
-		keyFile := filepath.Join(nr.keyDir, nr.ID())
-		_ = os.Remove(keyFile)
+	// the key is removed even if the mycelium service is not there
+	// (for example if the setup failed half way) so key material
+	// of deleted networks is not left on the node
+	keyFile := filepath.Join(nr.keyDir, nr.ID())
+	if err := os.Remove(keyFile); err != nil && !os.IsNotExist(err) {
+		log.Error().Err(err).Str("file", keyFile).Msg("failed to delete network mycelium key")
 	}
 
 	if bridge.Exists(nrBrName) {
@@ -632,7 +640,7 @@ func (nr *NetResource) Delete() error {
 	return nil
 }
 
-func (nr *NetResource) wgPeers() ([]*wireguard.Peer, error) {
+func (nr *NetResource) wgPeers(endpoints map[string]string) ([]*wireguard.Peer, error) {
 
 	wgPeers := make([]*wireguard.Peer, 0, len(nr.resource.Peers)+1)
 
@@ -643,10 +651,16 @@ func (nr *NetResource) wgPeers() ([]*wireguard.Peer, error) {
 			allowedIPs = append(allowedIPs, ip.String())
 		}
 
+		endpoint := peer.Endpoint
+		if wireguard.HasHostname(endpoint) {
+			// names are resolved by the caller in the host namespace
+			endpoint = endpoints[peer.WGPublicKey]
+		}
+
 		wgPeer := &wireguard.Peer{
 			PublicKey:  peer.WGPublicKey,
 			AllowedIPs: allowedIPs,
-			Endpoint:   peer.Endpoint,
+			Endpoint:   endpoint,
 		}
 
 		log.Info().Str("peer prefix", peer.Subnet.String()).Msg("generate wireguard configuration for peer")
@@ -813,6 +827,74 @@ func (nr *NetResource) HasWireguard() (bool, error) {
 	return exist, err
 }
 
+// WGDevice returns the wireguard device of the network resource
+func (nr *NetResource) WGDevice() (device *wgtypes.Device, err error) {
+	err = nr.withWG(func(wg *wireguard.Wireguard) error {
+		device, err = wg.Device()
+		return err
+	})
+
+	return device, err
+}
+
+// SetPeerEndpoint changes the endpoint of a single peer in place
+func (nr *NetResource) SetPeerEndpoint(publicKey string, endpoint *net.UDPAddr) error {
+	return nr.withWG(func(wg *wireguard.Wireguard) error {
+		return wg.SetPeerEndpoint(publicKey, endpoint)
+	})
+}
+
+// WGStatistics returns the counters of the wireguard interface of the network resource
+func (nr *NetResource) WGStatistics() (stats *netlink.LinkStatistics, err error) {
+	err = nr.withWG(func(wg *wireguard.Wireguard) error {
+		stats = wg.Attrs().Statistics
+		if stats == nil {
+			return fmt.Errorf("no statistics for wireguard interface %s", wg.Attrs().Name)
+		}
+		return nil
+	})
+
+	return stats, err
+}
+
+// WGAddrs returns the addresses set on the wireguard interface of the network resource
+func (nr *NetResource) WGAddrs() (addrs []netlink.Addr, err error) {
+	err = nr.withWG(func(wg *wireguard.Wireguard) error {
+		addrs, err = netlink.AddrList(wg, netlink.FAMILY_ALL)
+		return err
+	})
+
+	return addrs, err
+}
+
+// withWG runs fn inside the network resource namespace with its wireguard interface
+func (nr *NetResource) withWG(fn func(wg *wireguard.Wireguard) error) error {
+	nsName, err := nr.Namespace()
+	if err != nil {
+		return err
+	}
+
+	nrNetNS, err := namespace.GetByName(nsName)
+	if err != nil {
+		return err
+	}
+	defer nrNetNS.Close()
+
+	wgName, err := nr.WGName()
+	if err != nil {
+		return err
+	}
+
+	return nrNetNS.Do(func(_ ns.NetNS) error {
+		wg, err := wireguard.GetByName(wgName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get wireguard interface %s", wgName)
+		}
+
+		return fn(wg)
+	})
+}
+
 // SetWireguard sets wireguard of this network resource
 func (nr *NetResource) SetWireguard(wg *wireguard.Wireguard) error {
 	nsName, err := nr.Namespace()
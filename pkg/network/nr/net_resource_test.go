@@ -3,12 +3,16 @@ package nr
 import (
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/threefoldtech/zos/pkg"
+	"github.com/threefoldtech/zos/pkg/gridtypes"
+	"github.com/threefoldtech/zos/pkg/gridtypes/zos"
 	"github.com/vishvananda/netlink"
 )
 
@@ -117,6 +121,79 @@ func TestCreateBridge(t *testing.T) {
 	_ = netlink.LinkDel(l)
 }
 
+func TestWGPeers(t *testing.T) {
+	nr := New(pkg.Network{
+		NetID: "networkd1",
+		Network: zos.Network{
+			Peers: []zos.Peer{
+				{
+					Subnet:      gridtypes.MustParseIPNet("10.1.2.0/24"),
+					WGPublicKey: "mR5fBXohKe2MZ6v+GLwlKwrvkFxo1VvV3bPNHDBhOAI=",
+					AllowedIPs: []gridtypes.IPNet{
+						gridtypes.MustParseIPNet("10.1.2.0/24"),
+						gridtypes.MustParseIPNet("100.64.1.2/32"),
+					},
+					Endpoint: "37.187.124.71:51820",
+				},
+				{
+					Subnet:      gridtypes.MustParseIPNet("10.1.3.0/24"),
+					WGPublicKey: "kDd5mB6L4gkd3U5W287JeQu7urFzBYH51JQZUrJd8Hg=",
+					AllowedIPs: []gridtypes.IPNet{
+						gridtypes.MustParseIPNet("10.1.3.0/24"),
+					},
+				},
+				{
+					Subnet:      gridtypes.MustParseIPNet("10.1.4.0/24"),
+					WGPublicKey: "4DwTbGRWECH8oqcTXdoWXGOaWWC952QKbFE1fMzBNmA=",
+					AllowedIPs: []gridtypes.IPNet{
+						gridtypes.MustParseIPNet("10.1.4.0/24"),
+					},
+					Endpoint: "node.example.com:51820",
+				},
+				{
+					Subnet:      gridtypes.MustParseIPNet("10.1.5.0/24"),
+					WGPublicKey: "uJzU3qvwh9ERh5xOe2RL6cr4uO4OqFBbAHmsbkcp8Fs=",
+					AllowedIPs: []gridtypes.IPNet{
+						gridtypes.MustParseIPNet("10.1.5.0/24"),
+					},
+					Endpoint: "unresolved.example.com:51820",
+				},
+			},
+		},
+	}, "")
+
+	peers, err := nr.wgPeers(map[string]string{
+		"4DwTbGRWECH8oqcTXdoWXGOaWWC952QKbFE1fMzBNmA=": "1.2.3.4:51820",
+	})
+	require.NoError(t, err)
+	require.Len(t, peers, 4)
+
+	for _, peer := range peers {
+		require.NotNil(t, peer)
+		require.NotEmpty(t, peer.PublicKey)
+	}
+
+	assert.Equal(t, "37.187.124.71:51820", peers[0].Endpoint)
+	assert.Equal(t, []string{"10.1.2.0/24", "100.64.1.2/32"}, peers[0].AllowedIPs)
+	assert.Empty(t, peers[1].Endpoint)
+	assert.Equal(t, []string{"10.1.3.0/24"}, peers[1].AllowedIPs)
+	assert.Equal(t, "1.2.3.4:51820", peers[2].Endpoint)
+	assert.Empty(t, peers[3].Endpoint)
+}
+
+func TestDeleteRemovesKey(t *testing.T) {
+	keyDir := t.TempDir()
+	nr := New(pkg.Network{NetID: "nrkeytest"}, keyDir)
+
+	keyFile := filepath.Join(keyDir, nr.ID())
+	require.NoError(t, os.WriteFile(keyFile, []byte("key"), 0400))
+
+	require.NoError(t, nr.Delete())
+
+	_, err := os.Stat(keyFile)
+	require.True(t, os.IsNotExist(err))
+}
+
 func Test_wgIP(t *testing.T) {
 	type args struct {
 		subnet *net.IPNet
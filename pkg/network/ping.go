@@ -0,0 +1,130 @@
+package network
+
+import (
+	"math/rand"
+	"net"
+	"os"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/pkg/errors"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+
+	"github.com/threefoldtech/zos/pkg"
+	"github.com/threefoldtech/zos/pkg/network/namespace"
+	"github.com/threefoldtech/zos/pkg/network/nr"
+)
+
+const (
+	// pingTimeout is how long Ping waits for the echo reply
+	pingTimeout = 3 * time.Second
+
+	protoICMP   = 1
+	protoICMPv6 = 58
+)
+
+// Ping implements pkg.Networker interface
+func (n *networker) Ping(id pkg.NetID, target string) (rtt time.Duration, err error) {
+	ip := net.ParseIP(target)
+	if ip == nil {
+		return 0, errors.Errorf("invalid target ip '%s'", target)
+	}
+
+	netNR, err := n.networkOf(id)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to load network %s", id)
+	}
+
+	nsName, err := nr.New(netNR, n.myceliumKeyDir).Namespace()
+	if err != nil {
+		return 0, err
+	}
+
+	netNS, err := namespace.GetByName(nsName)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get network namespace %s", nsName)
+	}
+	defer netNS.Close()
+
+	err = netNS.Do(func(_ ns.NetNS) error {
+		rtt, err = ping(ip, pingTimeout)
+		return err
+	})
+
+	return rtt, err
+}
+
+// ping sends a single icmp echo request to ip from the current
+// network namespace and returns the round trip time of the reply
+func ping(ip net.IP, timeout time.Duration) (time.Duration, error) {
+	var (
+		network           = "ip4:icmp"
+		address           = "0.0.0.0"
+		proto             = protoICMP
+		echo    icmp.Type = ipv4.ICMPTypeEcho
+		reply   icmp.Type = ipv4.ICMPTypeEchoReply
+	)
+
+	if ip.To4() == nil {
+		network, address, proto = "ip6:ipv6-icmp", "::", protoICMPv6
+		echo, reply = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply
+	}
+
+	conn, err := icmp.ListenPacket(network, address)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to open icmp socket")
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, err
+	}
+
+	// raw sockets see all icmp traffic of the namespace so the
+	// id and sequence are used to find the reply to our request
+	request := icmp.Echo{
+		ID:   os.Getpid() & 0xffff,
+		Seq:  rand.Intn(0xffff),
+		Data: []byte("zos"),
+	}
+
+	msg := icmp.Message{Type: echo, Body: &request}
+	data, err := msg.Marshal(nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to build echo request")
+	}
+
+	start := time.Now()
+	if _, err := conn.WriteTo(data, &net.IPAddr{IP: ip}); err != nil {
+		return 0, errors.Wrapf(err, "failed to send echo request to %s", ip)
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		n, peer, err := conn.ReadFrom(buf)
+		if err, ok := err.(net.Error); ok && err.Timeout() {
+			return 0, errors.Errorf("no reply from %s after %s", ip, timeout)
+		} else if err != nil {
+			return 0, errors.Wrap(err, "failed to read echo reply")
+		}
+
+		addr, ok := peer.(*net.IPAddr)
+		if !ok || !addr.IP.Equal(ip) {
+			continue
+		}
+
+		msg, err := icmp.ParseMessage(proto, buf[:n])
+		if err != nil || msg.Type != reply {
+			continue
+		}
+
+		body, ok := msg.Body.(*icmp.Echo)
+		if !ok || body.ID != request.ID || body.Seq != request.Seq {
+			continue
+		}
+
+		return time.Since(start), nil
+	}
+}
@@ -0,0 +1,22 @@
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/icmp"
+)
+
+func TestPing(t *testing.T) {
+	conn, err := icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+	if err != nil {
+		t.Skip("raw icmp sockets are not permitted")
+	}
+	conn.Close()
+
+	rtt, err := ping(net.ParseIP("127.0.0.1"), time.Second)
+	require.NoError(t, err)
+	require.Greater(t, rtt, time.Duration(0))
+}
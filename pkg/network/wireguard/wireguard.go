@@ -1,6 +1,7 @@
 package wireguard
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -109,29 +110,40 @@ type Peer struct {
 
 // Configure configures the wiregard configuration
 func (w *Wireguard) Configure(privateKey string, listentPort int, peers []*Peer) error {
+	// validate the whole configuration before the interface is brought
+	// down, so a rejected config leaves the current tunnels running
+	peersConfig := make([]wgtypes.PeerConfig, len(peers))
+	for i, peer := range peers {
+		p, err := newPeer(peer.PublicKey, peer.Endpoint, peer.AllowedIPs)
+		if err != nil {
+			return errors.Wrapf(err, "invalid peer '%s'", peer.PublicKey)
+		}
+		peersConfig[i] = p
+	}
 
-	if err := netlink.LinkSetDown(w); err != nil {
+	if err := checkOverlap(peersConfig); err != nil {
 		return err
 	}
 
+	key, err := wgtypes.ParseKey(privateKey)
+	if err != nil {
+		return errors.Wrap(err, "invalid private key")
+	}
+
 	wc, err := wgctrl.New()
 	if err != nil {
 		return err
 	}
 	defer wc.Close()
 
-	peersConfig := make([]wgtypes.PeerConfig, len(peers))
-	for i, peer := range peers {
-		p, err := newPeer(peer.PublicKey, peer.Endpoint, peer.AllowedIPs)
-		if err != nil {
+	// the interface only goes down when its identity changes. a change
+	// of the peers alone is applied live so the local interface and its
+	// routes stay up
+	device, err := wc.Device(w.attrs.Name)
+	if err != nil || needsReset(device, key, listentPort) {
+		if err := netlink.LinkSetDown(w); err != nil {
 			return err
 		}
-		peersConfig[i] = p
-	}
-
-	key, err := wgtypes.ParseKey(privateKey)
-	if err != nil {
-		return err
 	}
 
 	config := wgtypes.Config{
@@ -152,6 +164,12 @@ func (w *Wireguard) Configure(privateKey string, listentPort int, peers []*Peer)
 	return nil
 }
 
+// needsReset checks if applying key and port to device changes the
+// identity of the interface
+func needsReset(device *wgtypes.Device, key wgtypes.Key, port int) bool {
+	return device.PrivateKey != key || device.ListenPort != port
+}
+
 func newPeer(pubkey, endpoint string, allowedIPs []string) (wgtypes.PeerConfig, error) {
 	peer := wgtypes.PeerConfig{
 		ReplaceAllowedIPs: true,
@@ -167,26 +185,16 @@ func newPeer(pubkey, endpoint string, allowedIPs []string) (wgtypes.PeerConfig,
 	}
 
 	if endpoint != "" {
-		host, p, err := net.SplitHostPort(endpoint)
-		if err != nil {
-			return peer, err
-		}
-
-		port, err := strconv.Atoi(p)
+		peer.Endpoint, err = parseEndpoint(endpoint)
 		if err != nil {
 			return peer, err
 		}
-
-		peer.Endpoint = &net.UDPAddr{
-			IP:   net.ParseIP(host),
-			Port: port,
-		}
 	}
 
 	for _, allowedIP := range allowedIPs {
 		ip, ipNet, err := net.ParseCIDR(allowedIP)
 		if err != nil {
-			return peer, err
+			return peer, errors.Wrapf(err, "invalid allowed ip '%s'", allowedIP)
 		}
 		ipNet.IP = ip
 		peer.AllowedIPs = append(peer.AllowedIPs, *ipNet)
@@ -195,6 +203,128 @@ func newPeer(pubkey, endpoint string, allowedIPs []string) (wgtypes.PeerConfig,
 	return peer, nil
 }
 
+// SetPeerEndpoint changes the endpoint of an existing peer. The rest of
+// the configuration and the other peers are left untouched, and the
+// interface stays up
+func (w *Wireguard) SetPeerEndpoint(publicKey string, endpoint *net.UDPAddr) error {
+	key, err := wgtypes.ParseKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	wc, err := wgctrl.New()
+	if err != nil {
+		return err
+	}
+	defer wc.Close()
+
+	config := wgtypes.Config{
+		Peers: []wgtypes.PeerConfig{
+			{
+				PublicKey:  key,
+				UpdateOnly: true,
+				Endpoint:   endpoint,
+			},
+		},
+	}
+
+	if err := wc.ConfigureDevice(w.attrs.Name, config); err != nil {
+		return errors.Wrapf(err, "failed to set endpoint of peer '%s'", publicKey)
+	}
+
+	return nil
+}
+
+// parseEndpoint parses a numeric endpoint in the form ip:port
+func parseEndpoint(endpoint string) (*net.UDPAddr, error) {
+	host, p, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := strconv.Atoi(p)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, fmt.Errorf("endpoint '%s' is not an ip address, host names must be resolved first", endpoint)
+	}
+
+	return &net.UDPAddr{
+		IP:   ip,
+		Port: port,
+	}, nil
+}
+
+// ResolveEndpoint parses a peer endpoint in the form host:port. The host is
+// either an IP or a host name that is resolved to its current address,
+// preferring an IPv4 address if the name has one. Resolve names from the
+// host namespace, and not from inside a network namespace
+func ResolveEndpoint(ctx context.Context, endpoint string) (*net.UDPAddr, error) {
+	if !HasHostname(endpoint) {
+		return parseEndpoint(endpoint)
+	}
+
+	host, p, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := net.DefaultResolver.LookupPort(ctx, "udp", p)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid port in endpoint '%s'", endpoint)
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve endpoint '%s'", endpoint)
+	}
+
+	ip := addrs[0].IP
+	for _, addr := range addrs {
+		if addr.IP.To4() != nil {
+			ip = addr.IP
+			break
+		}
+	}
+
+	return &net.UDPAddr{
+		IP:   ip,
+		Port: port,
+	}, nil
+}
+
+// HasHostname checks if the endpoint host is a name that needs to be
+// resolved rather than an IP
+func HasHostname(endpoint string) bool {
+	host, _, err := net.SplitHostPort(endpoint)
+	return err == nil && net.ParseIP(host) == nil
+}
+
+// checkOverlap makes sure no two peers claim the same allowed ip range.
+// Otherwise wireguard silently routes that range to the last peer. Nested
+// ranges are fine since wireguard picks the most specific one.
+func checkOverlap(peers []wgtypes.PeerConfig) error {
+	owners := make(map[string]wgtypes.Key)
+	for _, peer := range peers {
+		for _, ip := range peer.AllowedIPs {
+			// compare the ranges and not the addresses used to define them
+			key := (&net.IPNet{IP: ip.IP.Mask(ip.Mask), Mask: ip.Mask}).String()
+			if owner, ok := owners[key]; ok && owner != peer.PublicKey {
+				return fmt.Errorf(
+					"allowed ip '%s' is claimed by both peer '%s' and peer '%s'",
+					key, owner, peer.PublicKey,
+				)
+			}
+			owners[key] = peer.PublicKey
+		}
+	}
+
+	return nil
+}
+
 // GenerateKey generates a new private key. If key already exists
 // in that location, that key is returned instead.
 func GenerateKey(dir string) (wgtypes.Key, error) {
@@ -1,9 +1,12 @@
 package wireguard
 
 import (
+	"context"
+	"net"
 	"testing"
 
 	"github.com/vishvananda/netlink"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,6 +28,92 @@ func TestNewPeer(t *testing.T) {
 	require.Equal(t, allowedIps, tmp)
 }
 
+func TestNewPeerEndpoint(t *testing.T) {
+	publicKey := "mR5fBXohKe2MZ6v+GLwlKwrvkFxo1VvV3bPNHDBhOAI="
+
+	tests := []struct {
+		name     string
+		endpoint string
+		ip       net.IP
+		expected string
+	}{
+		{
+			name:     "ipv4",
+			endpoint: "1.2.3.4:51820",
+			ip:       net.ParseIP("1.2.3.4"),
+			expected: "1.2.3.4:51820",
+		},
+		{
+			name:     "ipv6",
+			endpoint: "[fe80::1]:51820",
+			ip:       net.ParseIP("fe80::1"),
+			expected: "[fe80::1]:51820",
+		},
+		{
+			name:     "mapped",
+			endpoint: "[::ffff:1.2.3.4]:51820",
+			ip:       net.ParseIP("1.2.3.4"),
+			expected: "1.2.3.4:51820",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peer, err := newPeer(publicKey, tt.endpoint, nil)
+			require.NoError(t, err)
+			require.NotNil(t, peer.Endpoint)
+			require.True(t, tt.ip.Equal(peer.Endpoint.IP))
+			require.Equal(t, 51820, peer.Endpoint.Port)
+			require.Equal(t, tt.expected, peer.Endpoint.String())
+		})
+	}
+
+	_, err := newPeer(publicKey, "fe80::1:51820", nil)
+	require.Error(t, err, "ipv6 endpoint without brackets is ambiguous")
+
+	_, err = newPeer(publicKey, "localhost:51820", nil)
+	require.Error(t, err, "host names are resolved before the peer is configured")
+}
+
+func TestResolveEndpoint(t *testing.T) {
+	ctx := context.Background()
+	addr, err := ResolveEndpoint(ctx, "1.2.3.4:51820")
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3.4:51820", addr.String())
+
+	addr, err = ResolveEndpoint(ctx, "localhost:51820")
+	require.NoError(t, err)
+	require.True(t, addr.IP.IsLoopback())
+	require.Equal(t, 51820, addr.Port)
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = ResolveEndpoint(ctx, "node.example.com:51820")
+	require.Error(t, err)
+}
+
+func TestHasHostname(t *testing.T) {
+	require.True(t, HasHostname("node.example.com:51820"))
+	require.False(t, HasHostname("1.2.3.4:51820"))
+	require.False(t, HasHostname("[fe80::1]:51820"))
+	require.False(t, HasHostname(""))
+}
+
+func TestNeedsReset(t *testing.T) {
+	key, err := wgtypes.ParseKey("4DwTbGRWECH8oqcTXdoWXGOaWWC952QKbFE1fMzBNmA=")
+	require.NoError(t, err)
+	other, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	device := &wgtypes.Device{PrivateKey: key, ListenPort: 51820}
+
+	require.False(t, needsReset(device, key, 51820))
+	require.True(t, needsReset(device, key, 51821))
+	require.True(t, needsReset(device, other, 51820))
+	// a new interface has no key yet
+	require.True(t, needsReset(&wgtypes.Device{}, key, 51820))
+}
+
 func TestConfigure(t *testing.T) {
 	wg, err := New("test")
 	require.NoError(t, err)
@@ -65,3 +154,40 @@ func TestConfigure(t *testing.T) {
 		assert.Equal(t, allowedIps, actual)
 	}
 }
+
+func TestCheckOverlap(t *testing.T) {
+	peer := func(key string, ips ...string) wgtypes.PeerConfig {
+		p, err := newPeer(key, "", ips)
+		require.NoError(t, err)
+		return p
+	}
+
+	const (
+		key1 = "mR5fBXohKe2MZ6v+GLwlKwrvkFxo1VvV3bPNHDBhOAI="
+		key2 = "4w4woC+AuDUAaRipT49M8SmTkzERps3xA5i0BW4XPns="
+	)
+
+	require.NoError(t, checkOverlap([]wgtypes.PeerConfig{
+		peer(key1, "10.1.1.0/24", "100.64.1.1/32"),
+		peer(key2, "10.1.2.0/24", "100.64.1.2/32"),
+	}))
+
+	require.Error(t, checkOverlap([]wgtypes.PeerConfig{
+		peer(key1, "10.1.1.0/24", "100.64.1.1/32"),
+		peer(key2, "10.1.2.0/24", "100.64.1.1/32"),
+	}), "same /32 on two peers")
+
+	require.Error(t, checkOverlap([]wgtypes.PeerConfig{
+		peer(key1, "10.1.1.1/24"),
+		peer(key2, "10.1.1.0/24"),
+	}), "same range written differently")
+
+	// wireguard routes to the most specific range
+	require.NoError(t, checkOverlap([]wgtypes.PeerConfig{
+		peer(key1, "10.1.0.0/16"),
+		peer(key2, "10.1.2.0/24"),
+	}))
+
+	_, err := newPeer(key1, "", []string{"10.1.1.0/33"})
+	require.Error(t, err)
+}
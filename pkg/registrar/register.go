@@ -7,6 +7,7 @@ import (
 	"net"
 	"time"
 
+	"github.com/cenkalti/backoff/v3"
 	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
@@ -94,8 +95,12 @@ func (r *Registrar) registration(ctx context.Context, cl zbus.Client, env enviro
 	return nodeID, twinID, nil
 }
 
-func retryNotify(err error, d time.Duration) {
-	log.Warn().Err(err).Str("sleep", d.String()).Msg("registration failed")
+// retryNotify logs failed registration attempts, attempt
+// points to the number of the attempt that failed
+func retryNotify(attempt *int) backoff.Notify {
+	return func(err error, d time.Duration) {
+		log.Warn().Err(err).Int("attempt", *attempt).Str("sleep", d.String()).Msg("registration failed")
+	}
 }
 
 func registerNode(
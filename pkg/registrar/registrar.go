@@ -115,7 +115,10 @@ func (r *Registrar) register(ctx context.Context, cl zbus.Client, env environmen
 	exp.MaxElapsedTime = 0 // retry indefinitely
 	bo := backoff.WithContext(exp, ctx)
 	register := func() {
+		attempt := 0
 		err := backoff.RetryNotify(func() error {
+			attempt++
+			log.Debug().Int("attempt", attempt).Msg("registering node")
 			nodeID, twinID, err := r.registration(ctx, cl, env, info)
 			if err != nil {
 				r.setState(FailedState(err))
@@ -124,7 +127,7 @@ func (r *Registrar) register(ctx context.Context, cl zbus.Client, env environmen
 				r.setState(DoneState(nodeID, twinID))
 			}
 			return nil
-		}, bo, retryNotify)
+		}, bo, retryNotify(&attempt))
 		if err != nil {
 			// this should never happen because we retry indefinitely
 			log.Error().Err(err).Msg("registration failed")
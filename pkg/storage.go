@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/threefoldtech/zos/pkg/gridtypes"
 	"github.com/threefoldtech/zos/pkg/gridtypes/zos"
@@ -136,7 +137,7 @@ type StorageModule interface {
 	// DiskFormat makes sure disk has filesystem, if it already formatted nothing happens
 	DiskFormat(name string) error
 
-	// DiskLookup looks up vdisk by name
+	// DiskLookup looks up vdisk by name, and returns its size and usage information
 	DiskLookup(name string) (VDisk, error)
 
 	// DiskExists checks if disk exists
@@ -145,7 +146,9 @@ type StorageModule interface {
 	// DiskDelete deletes a disk
 	DiskDelete(name string) error
 
+	// DiskList lists all virtual disks on the node
 	DiskList() ([]VDisk, error)
+
 	// Device management
 
 	//Devices list all "allocated" devices
@@ -174,6 +177,12 @@ type VDisk struct {
 	Path string
 	// Size in bytes
 	Size int64
+	// Used is the actual number of bytes allocated by the
+	// disk file on the underlying filesystem
+	Used int64
+	// Created is the creation time of the disk file. It's
+	// zero if the filesystem doesn't record it
+	Created time.Time
 }
 
 // Name returns the Name part of the disk path
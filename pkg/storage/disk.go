@@ -8,12 +8,14 @@ import (
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/g0rbe/go-chattr"
 	"github.com/pkg/errors"
 	log "github.com/rs/zerolog/log"
 	"github.com/threefoldtech/zos/pkg"
 	"github.com/threefoldtech/zos/pkg/gridtypes"
+	"golang.org/x/sys/unix"
 )
 
 const (
@@ -197,7 +199,8 @@ func (s *Module) DiskCreate(name string, size gridtypes.Unit) (disk pkg.VDisk, e
 	return pkg.VDisk{Path: path, Size: int64(size)}, nil
 }
 
-// DiskCreate with given size, return path to virtual disk (size in MB)
+// DiskResize grows the disk to the given size. A disk can't be shrunk since this
+// will truncate the data on it. Resizing to the current size is a no-op.
 func (s *Module) DiskResize(name string, size gridtypes.Unit) (disk pkg.VDisk, err error) {
 	path, err := s.findDisk(name)
 	if err != nil {
@@ -211,11 +214,22 @@ func (s *Module) DiskResize(name string, size gridtypes.Unit) (disk pkg.VDisk, e
 
 	defer file.Close()
 
+	stat, err := file.Stat()
+	if err != nil {
+		return disk, errors.Wrap(err, "failed to stat disk")
+	}
+
+	if int64(size) < stat.Size() {
+		return disk, fmt.Errorf("not safe to shrink disk '%s' from '%d' to '%d'", name, stat.Size(), size)
+	} else if int64(size) == stat.Size() {
+		return diskInfo(path)
+	}
+
 	if err = syscall.Fallocate(int(file.Fd()), 0, 0, int64(size)); err != nil {
 		return disk, errors.Wrap(err, "failed to truncate disk to size")
 	}
 
-	return pkg.VDisk{Path: path, Size: int64(size)}, nil
+	return diskInfo(path)
 }
 
 func (s *Module) ensureFS(disk string) error {
@@ -289,17 +303,29 @@ func (s *Module) DiskLookup(id string) (disk pkg.VDisk, err error) {
 		return disk, err
 	}
 
+	return diskInfo(path)
+}
+
+// diskInfo builds the vdisk information of the disk file at path
+func diskInfo(path string) (disk pkg.VDisk, err error) {
+	var stat unix.Statx_t
+	mask := unix.STATX_SIZE | unix.STATX_BLOCKS | unix.STATX_BTIME
+	if err := unix.Statx(unix.AT_FDCWD, path, 0, mask, &stat); err != nil {
+		return disk, &os.PathError{Op: "statx", Path: path, Err: err}
+	}
+
 	disk.Path = path
-	stat, err := os.Stat(path)
-	if err != nil {
-		return disk, err
+	disk.Size = int64(stat.Size)
+	// blocks are always counted in 512 bytes units
+	disk.Used = int64(stat.Blocks) * 512
+	if stat.Mask&unix.STATX_BTIME != 0 {
+		disk.Created = time.Unix(stat.Btime.Sec, int64(stat.Btime.Nsec))
 	}
 
-	disk.Size = stat.Size()
-	return
+	return disk, nil
 }
 
-// DiskList list all created disks
+// DiskList list all created disks on all pools
 func (s *Module) DiskList() ([]pkg.VDisk, error) {
 	pools, err := s.diskPools()
 	if err != nil {
@@ -318,18 +344,13 @@ func (s *Module) DiskList() ([]pkg.VDisk, error) {
 				continue
 			}
 
-			info, err := item.Info()
+			disk, err := diskInfo(filepath.Join(pool, item.Name()))
 			if err != nil {
 				return nil, errors.Wrapf(err, "failed to get file info for '%s'", item.Name())
 			}
 
-			disks = append(disks, pkg.VDisk{
-				Path: filepath.Join(pool, item.Name()),
-				Size: info.Size(),
-			})
+			disks = append(disks, disk)
 		}
-
-		return disks, nil
 	}
 
 	return disks, nil
@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskInfo(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "disk")
+	file, err := os.Create(path)
+	require.NoError(err)
+	require.NoError(file.Truncate(10 * 1024 * 1024))
+	_, err = file.Write(make([]byte, 4096))
+	require.NoError(err)
+	require.NoError(file.Close())
+
+	disk, err := diskInfo(path)
+	require.NoError(err)
+	require.Equal(path, disk.Path)
+	require.Equal("disk", disk.Name())
+	require.EqualValues(10*1024*1024, disk.Size)
+	// the file is sparse, only the written block is allocated
+	require.Less(disk.Used, disk.Size)
+
+	_, err = diskInfo(filepath.Join(t.TempDir(), "missing"))
+	require.True(os.IsNotExist(err))
+}
@@ -53,14 +53,22 @@ type Boot struct{}
 func (b Boot) DetectBootMethod() BootMethod {
 	log.Info().Msg("detecting boot method")
 
+	return detectBootMethod(TagFile, OldZosFile)
+}
+
+// detectBootMethod detects the boot method from the files that the
+// bootstrap process leaves behind. A node booted with kexec goes through
+// bootstrap again so it's detected the same way, while a development
+// VM (overlay) has none of these files.
+func detectBootMethod(tagFile, oldZosFile string) BootMethod {
 	// deprecated file. but if exists we still
 	// need to honor the method
-	if _, err := os.Stat(OldZosFile); err == nil {
+	if _, err := os.Stat(oldZosFile); err == nil {
 		// if this file existed so we booted normally with
 		return BootMethodBootstrap
 	}
 
-	if _, err := os.Stat(TagFile); err != nil {
+	if _, err := os.Stat(tagFile); err != nil {
 		return BootMethodOther
 	}
 
@@ -0,0 +1,34 @@
+package upgrade
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectBootMethod(t *testing.T) {
+	cases := []struct {
+		name     string
+		files    []string
+		expected BootMethod
+	}{
+		{name: "bootstrap", files: []string{"tag.info"}, expected: BootMethodBootstrap},
+		{name: "old bootstrap", files: []string{"flist.name"}, expected: BootMethodBootstrap},
+		{name: "both", files: []string{"tag.info", "flist.name"}, expected: BootMethodBootstrap},
+		{name: "overlay", expected: BootMethodOther},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, name := range c.files {
+				require.NoError(t, os.WriteFile(filepath.Join(root, name), nil, 0644))
+			}
+
+			method := detectBootMethod(filepath.Join(root, "tag.info"), filepath.Join(root, "flist.name"))
+			require.Equal(t, c.expected, method)
+		})
+	}
+}
@@ -255,7 +255,30 @@ func (h *HubClient) Download(cache, repo, name string) (string, error) {
 		return "", fmt.Errorf("failed to download flist: %s", response.Status)
 	}
 
-	return extracted, meta.Unpack(response.Body, extracted)
+	return extracted, unpack(response.Body, extracted)
+}
+
+// unpack extracts the flist archive to a temporary directory that is only
+// moved to extracted once the whole archive is read. Otherwise a download
+// that fails half way leaves a partial db that is later taken as cached
+func unpack(r io.Reader, extracted string) error {
+	tmp, err := os.MkdirTemp(filepath.Dir(extracted), filepath.Base(extracted)+".")
+	if err != nil {
+		return errors.Wrap(err, "failed to create download directory")
+	}
+	// this is a no-op once tmp is renamed
+	defer os.RemoveAll(tmp)
+
+	if err := meta.Unpack(r, tmp); err != nil {
+		return errors.Wrap(err, "failed to unpack flist")
+	}
+
+	// remove what is left of an older incomplete download
+	if err := os.RemoveAll(extracted); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, extracted)
 }
 
 // FList is information of flist as returned by repo list operation
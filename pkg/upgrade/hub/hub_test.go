@@ -1,8 +1,16 @@
 package hub
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -38,3 +46,35 @@ func TestHub(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, files)
 }
+
+func TestUnpack(t *testing.T) {
+	require := require.New(t)
+
+	data := make([]byte, 64*1024)
+	_, err := rand.Read(data)
+	require.NoError(err)
+
+	var archive bytes.Buffer
+	zw := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(zw)
+	require.NoError(tw.WriteHeader(&tar.Header{Name: "flistdb.sqlite3", Mode: 0644, Size: int64(len(data))}))
+	_, err = tw.Write(data)
+	require.NoError(err)
+	require.NoError(tw.Close())
+	require.NoError(zw.Close())
+
+	cache := t.TempDir()
+	extracted := filepath.Join(cache, "hash.d")
+
+	// a download that drops half way leaves nothing behind
+	err = unpack(bytes.NewReader(archive.Bytes()[:archive.Len()/2]), extracted)
+	require.True(errors.Is(err, io.ErrUnexpectedEOF))
+	entries, err := os.ReadDir(cache)
+	require.NoError(err)
+	require.Empty(entries)
+
+	require.NoError(unpack(bytes.NewReader(archive.Bytes()), extracted))
+	db, err := os.ReadFile(filepath.Join(extracted, "flistdb.sqlite3"))
+	require.NoError(err)
+	require.Equal(data, db)
+}
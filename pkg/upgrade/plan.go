@@ -0,0 +1,115 @@
+package upgrade
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/threefoldtech/0-fs/meta"
+	"github.com/threefoldtech/zos/pkg/upgrade/hub"
+)
+
+// UpgradePlan describes what the next update would do
+type UpgradePlan struct {
+	// Current is the currently installed tag
+	Current string `json:"current"`
+	// Target is the tag available for this node on the hub. If it's
+	// the same as Current the node is up to date
+	Target string `json:"target"`
+	// Allowed is false if the rollout configuration on the chain
+	// doesn't allow this node to update to Target yet, or if the
+	// update is outside of the upgrade window
+	Allowed bool `json:"allowed"`
+	// Window is the daily upgrade window, empty if updates can
+	// be applied at any time
+	Window string `json:"window,omitempty"`
+	// Packages is the list of packages that will be installed in order
+	Packages []string `json:"packages"`
+	// Services that will be restarted
+	Services []string `json:"services"`
+	// Files that will be written
+	Files []string `json:"files"`
+}
+
+// Plan resolves the next update without applying it. Only the flist
+// metadata of the packages is downloaded to the cache, nothing is
+// installed and no service is restarted.
+func (u *Upgrader) Plan(ctx context.Context) (plan UpgradePlan, err error) {
+	var from *hub.TagLink
+	current, err := u.boot.Current()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get info about current version, all packages are planned")
+	} else {
+		from = &current
+		plan.Current = current.Target
+	}
+
+	remote, err := u.target()
+	if err != nil {
+		return plan, errors.Wrap(err, "failed to get remote tag")
+	}
+
+	plan.Target = remote.Target
+	if remote.Target == current.Target {
+		return plan, nil
+	}
+
+	plan.Allowed, err = u.allows(ctx, current, remote)
+	if err != nil {
+		return plan, err
+	}
+
+	if u.window != nil {
+		plan.Window = u.window.String()
+		plan.Allowed = plan.Allowed && u.window.Contains(time.Now())
+	}
+
+	packages, err := u.pending(remote, from)
+	if err != nil {
+		return plan, err
+	}
+
+	for _, pkg := range packages {
+		plan.Packages = append(plan.Packages, pkg.String())
+
+		if err := u.planPackage(pkg, &plan); err != nil {
+			return plan, errors.Wrapf(err, "failed to plan package %s", pkg)
+		}
+	}
+
+	plan.Services = slices.DeleteFunc(plan.Services, func(e string) bool {
+		return slices.Contains(protected, e)
+	})
+
+	return plan, nil
+}
+
+// planPackage adds the files and services of a single package to the plan
+func (u *Upgrader) planPackage(pkg pkgRef, plan *UpgradePlan) error {
+	store, err := u.getFlist(pkg.repo, pkg.name, u)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	err = store.Walk("", func(path string, info meta.Meta) error {
+		if !info.IsDir() {
+			plan.Files = append(plan.Files, filepath.Join("/", path))
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list package files")
+	}
+
+	services, err := u.servicesFromStore(store)
+	if err != nil {
+		return errors.Wrap(err, "failed to list services from flist")
+	}
+
+	plan.Services = append(plan.Services, services...)
+	return nil
+}
@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"net"
+	"net/url"
 	"os"
 	"os/signal"
+	"path"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -15,6 +18,7 @@ import (
 	"time"
 
 	"github.com/blang/semver"
+	"github.com/cenkalti/backoff/v3"
 	"github.com/pkg/errors"
 
 	"github.com/threefoldtech/0-fs/meta"
@@ -34,6 +38,10 @@ var (
 	// ErrRestartNeeded is returned if upgraded requires a restart
 	ErrRestartNeeded = fmt.Errorf("restart needed")
 
+	// errUpdatePending is returned if an update is available but can't
+	// be applied before the upgrade window opens
+	errUpdatePending = fmt.Errorf("update is pending")
+
 	// services that can't be uninstalled with normal procedure
 	protected = []string{"identityd", "redis"}
 )
@@ -48,6 +56,14 @@ const (
 	checkJitter         = 10 // minutes
 	defaultHubTimeout   = 20 * time.Second
 
+	defaultHealthTimeout   = 2 * time.Minute
+	defaultDownloadRetries = 3
+
+	// healthSettle is how long a restarted service must keep running
+	// before it is considered healthy
+	healthSettle = 10 * time.Second
+	healthPoll   = time.Second
+
 	ZosRepo    = "tf-zos"
 	ZosPackage = "zos.flist"
 )
@@ -91,6 +107,22 @@ type Upgrader struct {
 	noZosUpgrade bool
 	hub          *hub.HubClient
 	storage      storage.Storage
+	window       *Window
+	pin          *pin
+
+	healthTimeout time.Duration
+	// failed is the last target that failed to install and was rolled
+	// back. It's not retried until the remote tag changes
+	failed string
+	// downloadRetries is how many times a failed flist
+	// download is retried before the update fails
+	downloadRetries uint64
+}
+
+// pin is a version the node is pinned to
+type pin struct {
+	version   string
+	downgrade bool
 }
 
 // UpgraderOption interface
@@ -129,6 +161,58 @@ func Storage(url string) UpgraderOption {
 	}
 }
 
+// UpgradeWindow option, only apply updates inside the given
+// daily window. By default updates are applied as soon as
+// they are detected
+func UpgradeWindow(w Window) UpgraderOption {
+	return func(u *Upgrader) error {
+		u.window = &w
+
+		return nil
+	}
+}
+
+// PinVersion option, pins the node to the given version (for example v3.10.2)
+// instead of following the latest version of its run mode. The node updates
+// to the pinned version as soon as possible, ignoring the rollout configuration
+// on the chain, and then stays on it.
+// Moving to a version that is older than the current one is refused unless
+// downgrade is set.
+func PinVersion(version string, downgrade bool) UpgraderOption {
+	return func(u *Upgrader) error {
+		if _, err := semver.ParseTolerant(version); err != nil {
+			return errors.Wrapf(err, "invalid pin version '%s'", version)
+		}
+
+		u.pin = &pin{version: version, downgrade: downgrade}
+		return nil
+	}
+}
+
+// HealthTimeout option, sets how long to wait for restarted services
+// to become healthy after a package is installed. If services are not
+// healthy by then the update fails (and is rolled back).
+// A zero value disables the check.
+func HealthTimeout(timeout time.Duration) UpgraderOption {
+	return func(u *Upgrader) error {
+		u.healthTimeout = timeout
+
+		return nil
+	}
+}
+
+// DownloadRetries option, sets how many times downloading an flist is
+// retried (with exponential backoff) when the connection drops or times
+// out while the flist is being unpacked. Failed requests are already
+// retried by the hub client, and other errors fail immediately.
+func DownloadRetries(retries uint64) UpgraderOption {
+	return func(u *Upgrader) error {
+		u.downloadRetries = retries
+
+		return nil
+	}
+}
+
 // Zinit option overrides the default zinit socket
 func Zinit(socket string) UpgraderOption {
 	return func(u *Upgrader) error {
@@ -142,8 +226,10 @@ func Zinit(socket string) UpgraderOption {
 func NewUpgrader(root string, opts ...UpgraderOption) (*Upgrader, error) {
 	hubClient := hub.NewHubClient(defaultHubTimeout)
 	u := &Upgrader{
-		root: root,
-		hub:  hubClient,
+		root:            root,
+		hub:             hubClient,
+		healthTimeout:   defaultHealthTimeout,
+		downloadRetries: defaultDownloadRetries,
 	}
 
 	for _, dir := range []string{u.fileCache(), u.flistCache()} {
@@ -189,7 +275,9 @@ func (u *Upgrader) Run(ctx context.Context) error {
 				return errors.Wrap(err, "failed to get remote tag")
 			}
 
-			if err := u.updateTo(remote, nil); err != nil {
+			// services are not gated on their health here since a failure
+			// can't be rolled back and would only make identityd exit
+			if _, err := u.updateTo(remote, nil, false); err != nil {
 				return errors.Wrap(err, "failed to run update")
 			}
 		}
@@ -207,19 +295,28 @@ func (u *Upgrader) Run(ctx context.Context) error {
 	// if the booting method is bootstrap then we run update periodically
 	// after u.nextUpdate to make sure all the modules are always up to date
 	for {
+		next := u.nextUpdate()
+
 		err := u.update(ctx)
 		if errors.Is(err, ErrRestartNeeded) {
 			return err
+		} else if errors.Is(err, errUpdatePending) {
+			// a window can be shorter than the time between two checks
+			// so wake up when it opens instead of possibly missing it
+			if until := u.windowWakeup(time.Now()); until < next {
+				next = until
+			}
 		} else if err != nil {
 			log.Error().Err(err).Msg("failed while checking for updates")
 			<-time.After(10 * time.Second)
 			continue
 		}
 
+		log.Info().Str("after", next.String()).Msg("checking for update")
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-time.After(u.nextUpdate()):
+		case <-time.After(next):
 		}
 
 	}
@@ -234,9 +331,15 @@ func (u *Upgrader) Version() semver.Version {
 // to make sure not all nodes run upgrader at the same time
 func (u *Upgrader) nextUpdate() time.Duration {
 	jitter := rand.Intn(checkJitter)
-	next := checkForUpdateEvery + (time.Duration(jitter) * time.Minute)
-	log.Info().Str("after", next.String()).Msg("checking for update")
-	return next
+	return checkForUpdateEvery + (time.Duration(jitter) * time.Minute)
+}
+
+// windowWakeup returns the time until the upgrade window opens, plus a
+// jitter so nodes that share the same window don't all check for the
+// update at once. The jitter is kept in the first half of the window
+func (u *Upgrader) windowWakeup(now time.Time) time.Duration {
+	spread := min(checkJitter*time.Minute, u.window.Duration()/2)
+	return u.window.Until(now) + time.Duration(rand.Int63n(int64(spread)+1))
 }
 
 // remote finds the `tag link` associated with the node network (for example devnet)
@@ -259,18 +362,73 @@ func (u *Upgrader) remote() (remote hub.TagLink, err error) {
 	return hub.NewTagLink(matches[0]), nil
 }
 
+// target returns the tag the node should be running. This is the remote
+// tag of the node run mode, or the pinned version if the node is pinned
+func (u *Upgrader) target() (hub.TagLink, error) {
+	remote, err := u.remote()
+	if err != nil || u.pin == nil {
+		return remote, err
+	}
+
+	repo, _, err := remote.Destination()
+	if err != nil {
+		return remote, err
+	}
+
+	remote.Target = path.Join(repo, "tags", u.pin.version)
+	return remote, nil
+}
+
+// allows checks if the node can update from current to remote now
+func (u *Upgrader) allows(ctx context.Context, current, remote hub.TagLink) (bool, error) {
+	if u.pin == nil {
+		return u.rolloutAllows(ctx, remote)
+	}
+
+	// a pinned version is chosen by the operator so the rollout
+	// configuration does not apply, but downgrades need to be explicit
+	if u.pin.downgrade || len(current.Target) == 0 {
+		return true, nil
+	}
+
+	from, err := semver.ParseTolerant(filepath.Base(current.Target))
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse current version '%s'", current.Target)
+	}
+
+	to, err := semver.ParseTolerant(u.pin.version)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse pinned version '%s'", u.pin.version)
+	}
+
+	if to.LT(from) {
+		log.Warn().
+			Stringer("current", from).
+			Stringer("pinned", to).
+			Msg("pinned version is older than current version, downgrades are not allowed")
+		return false, nil
+	}
+
+	return true, nil
+}
+
 func (u *Upgrader) update(ctx context.Context) error {
 	// here we need to do a normal full update cycle
-	current, err := u.boot.Current()
-	if err != nil {
-		log.Error().Err(err).Msg("failed to get info about current version, update anyway")
+	current, currentErr := u.boot.Current()
+	if currentErr != nil {
+		log.Error().Err(currentErr).Msg("failed to get info about current version, update anyway")
 	}
 
-	remote, err := u.remote()
+	remote, err := u.target()
 	if err != nil {
 		return errors.Wrap(err, "failed to get remote tag")
 	}
 
+	log.Debug().
+		Str("current", current.Target).
+		Str("remote", remote.Target).
+		Msg("resolved update target")
+
 	// obviously a remote tag need to match the current tag.
 	// if the remote is different, we actually run the update and exit.
 	if remote.Target == current.Target {
@@ -278,51 +436,170 @@ func (u *Upgrader) update(ctx context.Context) error {
 		return nil
 	}
 
+	if remote.Target == u.failed {
+		log.Debug().Str("version", filepath.Base(remote.Target)).Msg("skipping version that failed to install")
+		return nil
+	}
+
+	allowed, err := u.allows(ctx, current, remote)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return nil
+	}
+
+	if u.window != nil && !u.window.Contains(time.Now()) {
+		log.Info().
+			Str("version", filepath.Base(remote.Target)).
+			Stringer("window", u.window).
+			Msg("update is pending until the upgrade window")
+		return errUpdatePending
+	}
+
+	log.Info().Str("running version", u.Version().String()).Str("updating to version", filepath.Base(remote.Target)).Msg("updating system...")
+	if installed, err := u.updateTo(remote, &current, true); err != nil {
+		if len(installed) != 0 {
+			// the version itself is broken (for example a service that keeps
+			// crashing), retrying it would only flip the node between versions
+			log.Error().Str("version", filepath.Base(remote.Target)).Msg("version failed to install, it won't be retried until a new version is released")
+			u.failed = remote.Target
+		}
+
+		if currentErr == nil {
+			// some packages might have been installed already before the failure
+			// so we try to bring the node back to the current version
+			u.rollback(current, remote, installed)
+		}
+		return errors.Wrapf(err, "failed to update to new tag '%s'", remote.Target)
+	}
+
+	if err := u.boot.Set(remote); err != nil {
+		return err
+	}
+
+	return ErrRestartNeeded
+}
+
+// rolloutAllows checks the rollout configuration on the chain to see if
+// the node is allowed to update to remote now
+func (u *Upgrader) rolloutAllows(ctx context.Context, remote hub.TagLink) (bool, error) {
 	env := environment.MustGet()
 	gw := stubs.NewSubstrateGatewayStub(u.zcl)
 	chainVer, testFarms, err := getRolloutConfig(ctx, gw)
 	if err != nil {
-		return errors.Wrap(err, "failed to get rollout config and version")
+		return false, errors.Wrap(err, "failed to get rollout config and version")
 	}
 
 	remoteVer := remote.Target[strings.LastIndex(remote.Target, "/")+1:]
 
 	if env.RunningMode != environment.RunningDev && remoteVer != chainVer.Version {
 		// nothing to do! hub version is not the same as the chain
-		return nil
+		return false, nil
 	}
 
 	if !chainVer.SafeToUpgrade {
 		if !slices.Contains(testFarms, uint32(env.FarmID)) {
 			// nothing to do! waiting for the flag `safe to upgrade to be enabled after A/B testing`
 			// node is not a part of A/B testing
-			return nil
+			return false, nil
 		}
 	}
 
-	log.Info().Str("running version", u.Version().String()).Str("updating to version", filepath.Base(remote.Target)).Msg("updating system...")
-	if err := u.updateTo(remote, &current); err != nil {
-		return errors.Wrapf(err, "failed to update to new tag '%s'", remote.Target)
+	return true, nil
+}
+
+// rollback re-installs the packages of the current tag that were replaced by
+// the installed packages of the failed tag. This restores the node to the current
+// version after an update that failed half way. Packages that were never installed
+// are left alone so a failure before anything changed doesn't restart services.
+func (u *Upgrader) rollback(current, failed hub.TagLink, installed []pkgRef) {
+	if len(installed) == 0 {
+		log.Info().Str("failed", failed.Target).Msg("no package was installed, nothing to roll back")
+		return
 	}
 
-	if err := u.boot.Set(remote); err != nil {
-		return err
+	log.Warn().
+		Str("current", current.Target).
+		Str("failed", failed.Target).
+		Msg("rolling back to current version")
+
+	packages, err := u.pending(current, &failed)
+	if err != nil {
+		log.Error().Err(err).Str("tag", current.Target).Msg("failed to rollback to current version")
+		return
 	}
 
-	return ErrRestartNeeded
+	for _, pkg := range packages {
+		replaced := slices.ContainsFunc(installed, func(p pkgRef) bool {
+			return p.link == pkg.link
+		})
+
+		if !replaced {
+			continue
+		}
+
+		if _, err := u.install(pkg.repo, pkg.name, false); err != nil {
+			log.Error().Err(err).Str("tag", current.Target).Stringer("package", pkg).Msg("failed to rollback to current version")
+			return
+		}
+	}
+
+	log.Info().Str("tag", current.Target).Msg("rolled back to current version")
+}
+
+// pkgRef is a reference to a package flist on the hub
+type pkgRef struct {
+	repo string
+	name string
+	// link is the name of the package in the tag (for example zos.flist)
+	// which is the same across tags while name is versioned
+	link string
+}
+
+func (p pkgRef) String() string {
+	return fmt.Sprintf("%s/%s", p.repo, p.name)
 }
 
 // updateTo updates flist packages to match "link"
-// and only update zos package if u.noZosUpgrade is set to false
-func (u *Upgrader) updateTo(link hub.TagLink, current *hub.TagLink) error {
+// and only update zos package if u.noZosUpgrade is set to false.
+// It returns the packages that were installed, including one that failed
+// after its files were written, so a failed update can be rolled back.
+// If checkHealth is set, a package fails if its services are not healthy
+// after they are restarted
+func (u *Upgrader) updateTo(link hub.TagLink, current *hub.TagLink, checkHealth bool) (installed []pkgRef, err error) {
+	packages, err := u.pending(link, current)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pkg := range packages {
+		changed, err := u.install(pkg.repo, pkg.name, checkHealth)
+		if changed {
+			installed = append(installed, pkg)
+		}
+
+		if err != nil {
+			return installed, errors.Wrapf(err, "failed to install package %s", pkg)
+		}
+	}
+
+	return installed, nil
+}
+
+// pending returns the packages that need to be installed, in order, to
+// move from current to link. The zos package is always last and only
+// included if u.noZosUpgrade is set to false
+func (u *Upgrader) pending(link hub.TagLink, current *hub.TagLink) ([]pkgRef, error) {
 	repo, tag, err := link.Destination()
 	if err != nil {
-		return errors.Wrap(err, "failed to get destination tag")
+		return nil, errors.Wrap(err, "failed to get destination tag")
 	}
 
 	packages, err := u.hub.ListTag(repo, tag)
 	if err != nil {
-		return errors.Wrapf(err, "failed to list tag '%s' packages", tag)
+		return nil, errors.Wrapf(err, "failed to list tag '%s' packages", tag)
 	}
 
 	var curPkgsNames []string
@@ -330,11 +607,11 @@ func (u *Upgrader) updateTo(link hub.TagLink, current *hub.TagLink) error {
 		// get current pkgs list to compare the new pkgs against it
 		curRepo, curTag, err := current.Destination()
 		if err != nil {
-			return errors.Wrap(err, "failed to resolve current link")
+			return nil, errors.Wrap(err, "failed to resolve current link")
 		}
 		curPkgs, err := u.hub.ListTag(curRepo, curTag)
 		if err != nil {
-			return errors.Wrapf(err, "failed to list tag %s", curTag)
+			return nil, errors.Wrapf(err, "failed to list tag %s", curTag)
 		}
 		// store curPkgs names, the only part needed for the comparison
 		for _, pkg := range curPkgs {
@@ -345,7 +622,7 @@ func (u *Upgrader) updateTo(link hub.TagLink, current *hub.TagLink) error {
 		}
 	}
 
-	var later [][]string
+	var install, later []pkgRef
 	for _, pkg := range packages {
 		pkgRepo, name, err := pkg.Destination(repo)
 		// if the new pkg is the same as the current pkg no need to reinstall it
@@ -356,33 +633,22 @@ func (u *Upgrader) updateTo(link hub.TagLink, current *hub.TagLink) error {
 		if pkg.Name == ZosPackage {
 			// this is the last to do to make sure all dependencies are installed before updating zos
 			log.Debug().Str("repo", pkgRepo).Str("name", name).Msg("schedule package for later")
-			later = append(later, []string{pkgRepo, name})
+			later = append(later, pkgRef{repo: pkgRepo, name: name, link: pkg.Name})
 			continue
 		}
 
 		if err != nil {
-			return errors.Wrapf(err, "failed to find target for package '%s'", pkg.Target)
+			return nil, errors.Wrapf(err, "failed to find target for package '%s'", pkg.Target)
 		}
 
-		// install package
-		if err := u.install(pkgRepo, name); err != nil {
-			return errors.Wrapf(err, "failed to install package %s/%s", pkgRepo, name)
-		}
+		install = append(install, pkgRef{repo: pkgRepo, name: name, link: pkg.Name})
 	}
 
 	if u.noZosUpgrade {
-		return nil
-	}
-
-	// probably check flag for zos installation
-	for _, pkg := range later {
-		repo, name := pkg[0], pkg[1]
-		if err := u.install(repo, name); err != nil {
-			return errors.Wrapf(err, "failed to install package %s/%s", repo, name)
-		}
+		return install, nil
 	}
 
-	return nil
+	return append(install, later...), nil
 }
 
 func (u *Upgrader) flistCache() string {
@@ -395,7 +661,17 @@ func (u *Upgrader) fileCache() string {
 
 // getFlist accepts fqdn of flist as `<repo>/<name>.flist`
 func (u *Upgrader) getFlist(repo, name string, cache cache) (meta.Walker, error) {
-	db, err := u.hub.Download(cache.flistCache(), repo, name)
+	var db string
+	bo := backoff.WithMaxRetries(backoff.NewExponentialBackOff(), u.downloadRetries)
+	err := backoff.RetryNotify(func() (err error) {
+		db, err = u.hub.Download(cache.flistCache(), repo, name)
+		if err != nil && !isTransient(err) {
+			return backoff.Permanent(err)
+		}
+		return err
+	}, bo, func(err error, d time.Duration) {
+		log.Warn().Err(err).Str("sleep", d.String()).Msg("failed to download flist, retrying")
+	})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to download flist")
 	}
@@ -450,8 +726,9 @@ func (c *inMemoryCache) clean() {
 	os.RemoveAll(c.root)
 }
 
-// install from a single flist.
-func (u *Upgrader) install(repo, name string) error {
+// install from a single flist. changed is true once the package
+// files started to be written to the system, even if it then fails
+func (u *Upgrader) install(repo, name string, checkHealth bool) (changed bool, err error) {
 	log.Info().Str("repo", repo).Str("name", name).Msg("start installing package")
 	var cache cache = u
 	store, err := u.getFlist(repo, name, cache)
@@ -462,7 +739,7 @@ func (u *Upgrader) install(repo, name string) error {
 		// try in memory
 		inMemoryCache, err := newInMemoryCache()
 		if err != nil {
-			return fmt.Errorf("failed to create in memory cache: %w", err)
+			return false, fmt.Errorf("failed to create in memory cache: %w", err)
 		}
 		defer inMemoryCache.clean()
 		cache = inMemoryCache
@@ -470,10 +747,10 @@ func (u *Upgrader) install(repo, name string) error {
 		log.Info().Msg("downloading in memory")
 		store, err = u.getFlist(repo, name, cache)
 		if err != nil {
-			return errors.Wrapf(err, "failed to process flist: %s/%s", repo, name)
+			return false, errors.Wrapf(err, "failed to process flist: %s/%s", repo, name)
 		}
 	} else if err != nil {
-		return errors.Wrapf(err, "failed to process flist: %s/%s", repo, name)
+		return false, errors.Wrapf(err, "failed to process flist: %s/%s", repo, name)
 	}
 	defer store.Close()
 
@@ -482,15 +759,24 @@ func (u *Upgrader) install(repo, name string) error {
 		// the installation
 		return u.copyRecursive(store, "/", cache)
 	}); err != nil {
-		return errors.Wrapf(err, "failed to install flist: %s/%s", repo, name)
+		return true, errors.Wrapf(err, "failed to install flist: %s/%s", repo, name)
 	}
 
 	services, err := u.servicesFromStore(store)
 	if err != nil {
-		return errors.Wrap(err, "failed to list services from flist")
+		return true, errors.Wrap(err, "failed to list services from flist")
+	}
+
+	services = restartable(services)
+	if err := u.ensureRestarted(services...); err != nil {
+		return true, err
 	}
 
-	return u.ensureRestarted(services...)
+	if !checkHealth || u.healthTimeout == 0 {
+		return true, nil
+	}
+
+	return true, u.waitHealthy(u.healthTimeout, services...)
 }
 
 func (u *Upgrader) servicesFromStore(store meta.Walker) ([]string, error) {
@@ -522,11 +808,32 @@ func (u *Upgrader) servicesFromStore(store meta.Walker) ([]string, error) {
 	return services, err
 }
 
-func (u *Upgrader) ensureRestarted(service ...string) error {
-	// remove protected function from list, these never restarted
-	service = slices.DeleteFunc(service, func(e string) bool {
+// isTransient checks if a failed flist download can succeed if retried.
+// The hub client already retries failed requests, server errors and rate
+// limiting, so only a download that fails while the body is read (the
+// connection drops or the client timeout fires) is left to retry here
+func isTransient(err error) bool {
+	// request errors, including the ones the hub client gave up on
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		(errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// restartable removes the protected services, these are never restarted
+func restartable(services []string) []string {
+	return slices.DeleteFunc(services, func(e string) bool {
 		return slices.Contains(protected, e)
 	})
+}
+
+func (u *Upgrader) ensureRestarted(service ...string) error {
+	// remove protected function from list, these never restarted
+	service = restartable(service)
 
 	log.Debug().Strs("services", service).Msg("ensure services")
 	if len(service) == 0 {
@@ -561,6 +868,68 @@ func (u *Upgrader) ensureRestarted(service ...string) error {
 	return nil
 }
 
+// stability counts, per service, the consecutive polls a service was
+// seen healthy with the same pid. A service that crashes is restarted by
+// zinit with a new pid, which starts the count over
+type stability map[string]stable
+
+type stable struct {
+	pid   int
+	polls int
+}
+
+// observe records the status of a service and returns for how many
+// consecutive polls it has been healthy
+func (s stability) observe(status zinit.ServiceStatus) int {
+	last := s[status.Name]
+	if !status.State.Any(zinit.ServiceStateRunning, zinit.ServiceStateSuccess) {
+		delete(s, status.Name)
+		return 0
+	}
+
+	if last.polls == 0 || last.pid != status.Pid {
+		last = stable{pid: status.Pid}
+	}
+
+	last.polls++
+	s[status.Name] = last
+	return last.polls
+}
+
+// waitHealthy waits until all services are running (or exited successfully
+// for one shot services) and stayed that way for healthSettle, so a service
+// that crashes shortly after it starts is not taken as healthy. An error is
+// returned if any of the services is still not healthy after timeout
+func (u *Upgrader) waitHealthy(timeout time.Duration, services ...string) error {
+	deadline := time.Now().Add(timeout)
+	settle := int(healthSettle / healthPoll)
+	stable := make(stability)
+	for {
+		var unhealthy []string
+		for _, name := range services {
+			status, err := u.zinit.Status(name)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get service '%s' status", name)
+			}
+
+			if stable.observe(status) < settle {
+				unhealthy = append(unhealthy, name)
+			}
+		}
+
+		if len(unhealthy) == 0 {
+			log.Debug().Strs("services", services).Msg("services are healthy")
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("services are not healthy after '%s': %s", timeout, strings.Join(unhealthy, ", "))
+		}
+
+		time.Sleep(healthPoll)
+	}
+}
+
 func (u *Upgrader) copyRecursive(store meta.Walker, destination string, cache cache, skip ...string) error {
 	return store.Walk("", func(path string, info meta.Meta) error {
 		dest := filepath.Join(destination, path)
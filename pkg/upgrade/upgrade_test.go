@@ -1,13 +1,24 @@
 package upgrade
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	"github.com/threefoldtech/0-fs/meta"
 	"github.com/threefoldtech/zos/pkg/upgrade/hub"
+	"github.com/threefoldtech/zos/pkg/zinit"
 )
 
 func TestUpgraderDownload(t *testing.T) {
@@ -54,3 +65,107 @@ func TestUpgraderDownload(t *testing.T) {
 
 	require.NoError(err)
 }
+
+func TestPinAllows(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	current := hub.TagLink{FList: hub.FList{Target: "tf-autobuilder/tags/v3.10.0"}}
+	at := func(version string) hub.TagLink {
+		return hub.TagLink{FList: hub.FList{Target: "tf-autobuilder/tags/" + version}}
+	}
+
+	var u Upgrader
+	require.NoError(PinVersion("v3.11.0", false)(&u))
+	ok, err := u.allows(ctx, current, at("v3.11.0"))
+	require.NoError(err)
+	require.True(ok)
+
+	require.NoError(PinVersion("v3.9.1", false)(&u))
+	ok, err = u.allows(ctx, current, at("v3.9.1"))
+	require.NoError(err)
+	require.False(ok, "downgrade must be explicitly allowed")
+
+	require.NoError(PinVersion("v3.9.1", true)(&u))
+	ok, err = u.allows(ctx, current, at("v3.9.1"))
+	require.NoError(err)
+	require.True(ok)
+
+	require.Error(PinVersion("latest", false)(&u))
+}
+
+func TestIsTransient(t *testing.T) {
+	require := require.New(t)
+
+	require.True(isTransient(errors.Wrap(io.ErrUnexpectedEOF, "failed to unpack flist")))
+	// retried by the hub client already
+	require.False(isTransient(fmt.Errorf("GET giving up after 6 attempt(s): %w", &url.Error{Op: "Get", Err: syscall.ETIMEDOUT})))
+	require.False(isTransient(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}))
+	require.False(isTransient(fmt.Errorf("failed to download flist: 404 Not Found")))
+	require.False(isTransient(syscall.EROFS))
+}
+
+func TestIsTransientBodyTimeout(t *testing.T) {
+	require := require.New(t)
+
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Length", "1024")
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	client := http.Client{Timeout: 100 * time.Millisecond}
+	response, err := client.Get(server.URL)
+	require.NoError(err)
+	defer response.Body.Close()
+
+	_, err = io.ReadAll(response.Body)
+	require.Error(err)
+	require.True(isTransient(errors.Wrap(err, "failed to unpack flist")))
+}
+
+func TestStability(t *testing.T) {
+	require := require.New(t)
+
+	status := func(state string, pid int) zinit.ServiceStatus {
+		s := zinit.ServiceStatus{Name: "noded", Pid: pid}
+		require.NoError(s.State.UnmarshalText([]byte(state)))
+		return s
+	}
+
+	stable := make(stability)
+	require.Equal(1, stable.observe(status("running", 10)))
+	require.Equal(2, stable.observe(status("running", 10)))
+
+	// crashed and restarted by zinit
+	require.Equal(1, stable.observe(status("running", 11)))
+	require.Equal(0, stable.observe(status("error", 0)))
+	require.Equal(0, stable.observe(status("spawned", 12)))
+	require.Equal(1, stable.observe(status("running", 12)))
+
+	// one shot services stay healthy once they exit
+	require.Equal(1, stable.observe(status("success", 0)))
+	require.Equal(2, stable.observe(status("success", 0)))
+}
+
+func TestWindowWakeup(t *testing.T) {
+	require := require.New(t)
+
+	w, err := ParseWindow("02:00-02:04")
+	require.NoError(err)
+	u := Upgrader{window: &w}
+
+	now, err := time.Parse("15:04", "01:00")
+	require.NoError(err)
+
+	for i := 0; i < 100; i++ {
+		next := u.windowWakeup(now)
+		require.GreaterOrEqual(next, time.Hour)
+		// never past the first half of the window
+		require.LessOrEqual(next, time.Hour+2*time.Minute)
+	}
+}
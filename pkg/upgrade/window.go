@@ -0,0 +1,95 @@
+package upgrade
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a daily time window (in UTC) in which updates are allowed
+// to be applied. A window can span midnight (for example 22:00-02:00)
+type Window struct {
+	start time.Duration
+	end   time.Duration
+}
+
+// ParseWindow parses a window in the form `HH:MM-HH:MM`
+func ParseWindow(s string) (w Window, err error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return w, fmt.Errorf("invalid window '%s' expected format HH:MM-HH:MM", s)
+	}
+
+	if w.start, err = parseClock(from); err != nil {
+		return w, err
+	}
+
+	if w.end, err = parseClock(to); err != nil {
+		return w, err
+	}
+
+	if w.start == w.end {
+		return w, fmt.Errorf("invalid window '%s' start and end can't be the same", s)
+	}
+
+	return w, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time '%s' expected format HH:MM", s)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains checks if t falls inside the window
+func (w Window) Contains(t time.Time) bool {
+	t = t.UTC()
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+
+	if w.start < w.end {
+		return now >= w.start && now < w.end
+	}
+
+	// window spans midnight
+	return now >= w.start || now < w.end
+}
+
+// Until returns how long after t the window opens next. It's zero
+// if t is inside the window
+func (w Window) Until(t time.Time) time.Duration {
+	if w.Contains(t) {
+		return 0
+	}
+
+	t = t.UTC()
+	now := time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+
+	until := w.start - now
+	if until < 0 {
+		until += 24 * time.Hour
+	}
+
+	return until
+}
+
+// Duration returns how long the window stays open
+func (w Window) Duration() time.Duration {
+	if w.start < w.end {
+		return w.end - w.start
+	}
+
+	return 24*time.Hour - w.start + w.end
+}
+
+func (w Window) String() string {
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+	}
+
+	return fmt.Sprintf("%s-%s", clock(w.start), clock(w.end))
+}
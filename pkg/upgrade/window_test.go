@@ -0,0 +1,74 @@
+package upgrade
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWindow(t *testing.T) {
+	w, err := ParseWindow("02:00-04:30")
+	require.NoError(t, err)
+	require.Equal(t, "02:00-04:30", w.String())
+
+	for _, invalid := range []string{"", "02:00", "2am-4am", "25:00-01:00", "02:00-02:00"} {
+		_, err := ParseWindow(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestWindowContains(t *testing.T) {
+	at := func(clock string) time.Time {
+		t, err := time.Parse("15:04", clock)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+
+	w, err := ParseWindow("02:00-04:00")
+	require.NoError(t, err)
+
+	require.False(t, w.Contains(at("01:59")))
+	require.True(t, w.Contains(at("02:00")))
+	require.True(t, w.Contains(at("03:30")))
+	require.False(t, w.Contains(at("04:00")))
+
+	// window that spans midnight
+	w, err = ParseWindow("22:00-02:00")
+	require.NoError(t, err)
+
+	require.True(t, w.Contains(at("23:00")))
+	require.True(t, w.Contains(at("00:30")))
+	require.False(t, w.Contains(at("02:00")))
+	require.False(t, w.Contains(at("12:00")))
+}
+
+func TestWindowUntil(t *testing.T) {
+	at := func(clock string) time.Time {
+		t, err := time.Parse("15:04:05", clock)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+
+	w, err := ParseWindow("02:00-02:30")
+	require.NoError(t, err)
+
+	require.Equal(t, time.Duration(0), w.Until(at("02:10:00")))
+	require.Equal(t, 30*time.Second, w.Until(at("01:59:30")))
+	require.Equal(t, 23*time.Hour+30*time.Minute, w.Until(at("02:30:00")))
+	require.True(t, w.Contains(at("01:59:30").Add(w.Until(at("01:59:30")))))
+}
+
+func TestWindowDuration(t *testing.T) {
+	w, err := ParseWindow("02:00-02:30")
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Minute, w.Duration())
+
+	w, err = ParseWindow("22:00-02:00")
+	require.NoError(t, err)
+	require.Equal(t, 4*time.Hour, w.Duration())
+}